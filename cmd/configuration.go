@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/happyagosmith/jig/internal/entities"
+	"github.com/happyagosmith/jig/internal/filehandler/model"
 	"github.com/happyagosmith/jig/internal/issuetrackers"
 	"github.com/happyagosmith/jig/internal/parsers"
 	"github.com/happyagosmith/jig/internal/repo"
@@ -38,17 +40,48 @@ func (v *issuePatternsValue) String() string {
 
 const (
 	CustomCommitPattern     = "customCommitPattern"
+	GitProvider             = "gitProvider"
+	GitProfiles             = "gitProfiles"
+	HTTPTrackers            = "httpTrackers"
+	IssueTrackers           = "issueTrackers"
 	GitURL                  = "gitURL"
 	GitToken                = "gitToken"
 	GitMRBranch             = "gitMRBranch"
+	GitPageSize             = "gitPageSize"
+	GitMaxPages             = "gitMaxPages"
+	GitKnownIssuesLabels    = "gitKnownIssuesLabels"
+	GitKnownIssuesMilestone = "gitKnownIssuesMilestone"
+	GitKnownIssuesType      = "gitKnownIssuesType"
+	GitLabelCategories      = "gitLabelCategories"
+	GitDefaultCategory      = "gitDefaultCategory"
+	GitIssueTypeCategories  = "gitIssueTypeCategories"
 	JiraURL                 = "jiraURL"
+	JiraFlavour             = "jiraFlavour"
 	JiraUsername            = "jiraUsername"
 	JiraPassword            = "jiraPassword"
 	JiraClosedFeatureFilter = "jiraClosedFeatureFilter"
 	JiraFixedBugFilter      = "jiraFixedBugFilter"
 	JiraKnownIssuesJQL      = "jiraKnownIssuesJQL"
+	JiraBrowseURL           = "jiraBrowseURL"
+	JiraFields              = "jiraFields"
+	JiraFixVersion          = "jiraFixVersion"
+	JiraEpicRollUp          = "jiraEpicRollUp"
+	JiraEpicLinkField       = "jiraEpicLinkField"
+	JiraSearchPageSize      = "jiraSearchPageSize"
+	JiraKeysBatchSize       = "jiraKeysBatchSize"
 	IssuePatterns           = "issuePatterns"
 	WithCCWithoutScope      = "withCCWithoutScope"
+	ScopeSeparators         = "scopeSeparators"
+	CommitTypeCategories    = "commitTypeCategories"
+	Categories              = "categories"
+)
+
+// Default values of the jira settings holding queries, also used by the Jira
+// sites declared in issueTrackers when their options are not set.
+const (
+	defaultJiraClosedFeatureFilter = "Story:GOLIVE,TECH TASK:Completata"
+	defaultJiraFixedBugFilter      = "BUG:FIXED,BUG:RELEASED"
+	defaultJiraKnownIssuesJQL      = "status not in (Done, RELEASED, Fixed, GOLIVE, Cancelled) AND issuetype in (Bug, \"TECH DEBT\")"
 )
 
 func GetConfigString(key string) string {
@@ -59,6 +92,39 @@ func GetConfigBool(key string) bool {
 	return viper.GetBool(key)
 }
 
+func GetConfigInt(key string) int {
+	return viper.GetInt(key)
+}
+
+// getConfigList returns the values of a list, set either as a yaml list in the
+// config file or as values separated by comma, ignoring the empty values.
+func getConfigList(key string) []string {
+	if s, ok := viper.Get(key).(string); ok {
+		return splitList(s)
+	}
+
+	var values []string
+	for _, v := range viper.GetStringSlice(key) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// splitList returns the values separated by comma, ignoring the empty values.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
 func GetIssuePatterns() []parsers.IssuePattern {
 	var issuePatterns []parsers.IssuePattern
 
@@ -84,6 +150,152 @@ func GetIssuePatterns() []parsers.IssuePattern {
 	return nil
 }
 
+// CategoryConfig declares a category of issues listed in its own generated
+// value, matching the issues by Jira type and status, label, commit type or
+// commit category.
+type CategoryConfig struct {
+	Name             string   `yaml:"name" mapstructure:"name"`
+	IssueTypes       []string `yaml:"issueTypes" mapstructure:"issueTypes"`
+	Labels           []string `yaml:"labels" mapstructure:"labels"`
+	CommitTypes      []string `yaml:"commitTypes" mapstructure:"commitTypes"`
+	CommitCategories []string `yaml:"commitCategories" mapstructure:"commitCategories"`
+	Bump             string   `yaml:"bump" mapstructure:"bump"`
+}
+
+func GetCategories() ([]CategoryConfig, error) {
+	var categories []CategoryConfig
+	if err := viper.UnmarshalKey(Categories, &categories); err != nil {
+		return nil, fmt.Errorf("error unmarshaling categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// ConfigureCategories returns the model options adding the declared categories.
+func ConfigureCategories() ([]model.ModelOpt, error) {
+	categories, err := GetCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []model.ModelOpt
+	for _, c := range categories {
+		fmt.Printf("using category %s\n", c.Name)
+		var commitCategories []entities.CommitCategory
+		for _, cc := range c.CommitCategories {
+			category, err := entities.ParseCommitCategory(strings.TrimSpace(cc))
+			if err != nil {
+				return nil, fmt.Errorf("invalid category %q: %w", c.Name, err)
+			}
+			commitCategories = append(commitCategories, category)
+		}
+		opts = append(opts, model.WithCategory(model.Category{
+			Name:             c.Name,
+			IssueTypes:       c.IssueTypes,
+			Labels:           c.Labels,
+			CommitTypes:      c.CommitTypes,
+			CommitCategories: commitCategories,
+			Bump:             model.Bump(strings.ToLower(c.Bump)),
+		}))
+	}
+
+	return opts, nil
+}
+
+// GitProfile holds the connection details of a git provider and its git
+// options, the ones not set defaulting to the git settings. The services of the
+// model select a profile by name with the gitProfile field.
+type GitProfile struct {
+	Name       string `yaml:"name" mapstructure:"name"`
+	Provider   string `yaml:"provider" mapstructure:"provider"`
+	URL        string `yaml:"url" mapstructure:"url"`
+	Token      string `yaml:"token" mapstructure:"token"`
+	GitOptions `yaml:",inline" mapstructure:",squash"`
+}
+
+// GitOptions holds the settings of a git provider, with the categories
+// expressed as list name:category separated by comma.
+type GitOptions struct {
+	PageSize             int      `yaml:"pageSize" mapstructure:"pageSize"`
+	MaxPages             int      `yaml:"maxPages" mapstructure:"maxPages"`
+	LabelCategories      string   `yaml:"labelCategories" mapstructure:"labelCategories"`
+	IssueTypeCategories  string   `yaml:"issueTypeCategories" mapstructure:"issueTypeCategories"`
+	DefaultCategory      string   `yaml:"defaultCategory" mapstructure:"defaultCategory"`
+	KnownIssuesLabels    []string `yaml:"knownIssuesLabels" mapstructure:"knownIssuesLabels"`
+	KnownIssuesMilestone string   `yaml:"knownIssuesMilestone" mapstructure:"knownIssuesMilestone"`
+	KnownIssuesType      string   `yaml:"knownIssuesType" mapstructure:"knownIssuesType"`
+}
+
+func getGitOptions() GitOptions {
+	return GitOptions{
+		PageSize:             GetConfigInt(GitPageSize),
+		MaxPages:             GetConfigInt(GitMaxPages),
+		LabelCategories:      strings.Join(getConfigList(GitLabelCategories), ","),
+		IssueTypeCategories:  strings.Join(getConfigList(GitIssueTypeCategories), ","),
+		DefaultCategory:      GetConfigString(GitDefaultCategory),
+		KnownIssuesLabels:    getConfigList(GitKnownIssuesLabels),
+		KnownIssuesMilestone: GetConfigString(GitKnownIssuesMilestone),
+		KnownIssuesType:      GetConfigString(GitKnownIssuesType),
+	}
+}
+
+// withDefaults returns the options with the ones not set taken from d.
+func (o GitOptions) withDefaults(d GitOptions) GitOptions {
+	knownIssuesLabels := o.KnownIssuesLabels
+	if len(knownIssuesLabels) == 0 {
+		knownIssuesLabels = d.KnownIssuesLabels
+	}
+
+	return GitOptions{
+		PageSize:             cmp.Or(o.PageSize, d.PageSize),
+		MaxPages:             cmp.Or(o.MaxPages, d.MaxPages),
+		LabelCategories:      cmp.Or(o.LabelCategories, d.LabelCategories),
+		IssueTypeCategories:  cmp.Or(o.IssueTypeCategories, d.IssueTypeCategories),
+		DefaultCategory:      cmp.Or(o.DefaultCategory, d.DefaultCategory),
+		KnownIssuesLabels:    knownIssuesLabels,
+		KnownIssuesMilestone: cmp.Or(o.KnownIssuesMilestone, d.KnownIssuesMilestone),
+		KnownIssuesType:      cmp.Or(o.KnownIssuesType, d.KnownIssuesType),
+	}
+}
+
+func GetGitProfiles() ([]GitProfile, error) {
+	var profiles []GitProfile
+	if err := viper.UnmarshalKey(GitProfiles, &profiles); err != nil {
+		return nil, fmt.Errorf("error unmarshaling git profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// JiraField maps the Jira field, identified by id or name, to the name used in
+// the fields of the issue details.
+type JiraField struct {
+	Name  string `yaml:"name" mapstructure:"name"`
+	Field string `yaml:"field" mapstructure:"field"`
+}
+
+// GetJiraFields returns the Jira field mappings, set either as a list in the
+// config file or as a list name:field separated by comma.
+func GetJiraFields() ([]JiraField, error) {
+	var fields []JiraField
+	if _, ok := viper.Get(JiraFields).(string); !ok {
+		if err := viper.UnmarshalKey(JiraFields, &fields); err != nil {
+			return nil, fmt.Errorf("error unmarshaling jira fields: %w", err)
+		}
+		return fields, nil
+	}
+
+	for _, nf := range getConfigList(JiraFields) {
+		name, field, found := strings.Cut(nf, ":")
+		if !found {
+			return nil, fmt.Errorf("wrong format of %s, expected list name:field separated by comma", JiraFields)
+		}
+		fields = append(fields, JiraField{Name: strings.TrimSpace(name), Field: strings.TrimSpace(field)})
+	}
+
+	return fields, nil
+}
+
 var cfgFile string
 
 func InitConfiguration(cmd *cobra.Command) {
@@ -106,7 +318,11 @@ func InitConfiguration(cmd *cobra.Command) {
 		},
 		{
 			IssueTracker: "git",
-			Pattern:      `#(\d+)`,
+			Pattern:      `https?://[^/\s]+/(?P<project>[\w.-]+(?:/[\w.-]+)+?)(?:/-)?/(?:issues|pull)/(?P<key>\d+)`,
+		},
+		{
+			IssueTracker: "git",
+			Pattern:      `(?:(?P<project>[\w.-]+(?:/[\w.-]+)+))?#(?P<key>\d+)`,
 		},
 	}
 	cmd.PersistentFlags().Var(&issuePatterns, IssuePatterns, "Issue patterns used to determine the issue tracker associated with each issue key")
@@ -118,32 +334,89 @@ func InitConfiguration(cmd *cobra.Command) {
 	cmd.PersistentFlags().Bool(WithCCWithoutScope, false, "if true, extract conventional commit without scope")
 	viper.BindPFlag(WithCCWithoutScope, cmd.PersistentFlags().Lookup(WithCCWithoutScope))
 
+	cmd.PersistentFlags().String(ScopeSeparators, parsers.DefaultScopeSeparators, "Characters separating the issue keys in the scope of the commit and merge request titles, e.g. feat(ABC-1,ABC-2)")
+	viper.BindPFlag(ScopeSeparators, cmd.PersistentFlags().Lookup(ScopeSeparators))
+
+	cmd.PersistentFlags().String(CommitTypeCategories, "feat:FEATURE,fix:BUG_FIX,perf:IMPROVEMENT,refactor:IMPROVEMENT,security:SECURITY,deprecate:DEPRECATION", "List of type:category that identify the category of the conventional commits by their type. The categories are FEATURE, BUG_FIX, IMPROVEMENT, SECURITY and DEPRECATION")
+	viper.BindPFlag(CommitTypeCategories, cmd.PersistentFlags().Lookup(CommitTypeCategories))
+
 	cmd.PersistentFlags().String(CustomCommitPattern, `\[(?P<scope>[^\]]*)\](?P<subject>.*)`, "Custom pattern to apply on the commit and merge request title to extract the issue keys and the summary. If the message is not a conventional commit message, this custom pattern is applied. The pattern should include the named groups scope and subject")
 	viper.BindPFlag(CustomCommitPattern, cmd.PersistentFlags().Lookup(CustomCommitPattern))
 
+	cmd.PersistentFlags().String(GitProvider, "gitlab", "Git provider hosting the repositories: gitlab, github or local. With local, gitURL is the base path of the local repositories")
+	viper.BindPFlag(GitProvider, cmd.PersistentFlags().Lookup(GitProvider))
+
 	cmd.PersistentFlags().String(GitURL, "", "Git base URL")
 	viper.BindPFlag(GitURL, cmd.PersistentFlags().Lookup(GitURL))
 
 	cmd.PersistentFlags().String(GitToken, "", "Git token with read REST API permissions")
 	viper.BindPFlag(GitToken, cmd.PersistentFlags().Lookup(GitToken))
 
+	cmd.PersistentFlags().Int(GitPageSize, 100, "Number of items requested for each page of the GitLab list calls")
+	viper.BindPFlag(GitPageSize, cmd.PersistentFlags().Lookup(GitPageSize))
+
+	cmd.PersistentFlags().Int(GitMaxPages, 50, "Max number of pages retrieved for each GitLab list call")
+	viper.BindPFlag(GitMaxPages, cmd.PersistentFlags().Lookup(GitMaxPages))
+
+	cmd.PersistentFlags().String(GitLabelCategories, "feature:CLOSED_FEATURE,enhancement:CLOSED_FEATURE,bug:FIXED_BUG", "List of filters label:category that identify the category of the GitLab and GitHub issues, in order of precedence. A label scope::* matches all the scoped labels of the scope")
+	viper.BindPFlag(GitLabelCategories, cmd.PersistentFlags().Lookup(GitLabelCategories))
+
+	cmd.PersistentFlags().String(GitDefaultCategory, "", "Category of the GitLab and GitHub issues not matching any label of gitLabelCategories. If not specified, OTHER for GitLab and CLOSED_FEATURE for GitHub")
+	viper.BindPFlag(GitDefaultCategory, cmd.PersistentFlags().Lookup(GitDefaultCategory))
+
+	cmd.PersistentFlags().String(GitIssueTypeCategories, "", "List of filters type:category that identify the category of the GitHub issues by issue type, taking precedence over gitLabelCategories. If not specified, the issue types are not retrieved")
+	viper.BindPFlag(GitIssueTypeCategories, cmd.PersistentFlags().Lookup(GitIssueTypeCategories))
+
+	cmd.PersistentFlags().String(GitKnownIssuesLabels, "", "List of labels separated by comma of the open GitLab or GitHub issues retrieved as known issues. If no known issues filter is specified, the open GitHub issues labelled bug are retrieved")
+	viper.BindPFlag(GitKnownIssuesLabels, cmd.PersistentFlags().Lookup(GitKnownIssuesLabels))
+
+	cmd.PersistentFlags().String(GitKnownIssuesMilestone, "", "Milestone of the open GitLab or GitHub issues retrieved as known issues")
+	viper.BindPFlag(GitKnownIssuesMilestone, cmd.PersistentFlags().Lookup(GitKnownIssuesMilestone))
+
+	cmd.PersistentFlags().String(GitKnownIssuesType, "", "Type of the open issues retrieved as known issues: issue, incident or test_case for GitLab, the issue type for GitHub")
+	viper.BindPFlag(GitKnownIssuesType, cmd.PersistentFlags().Lookup(GitKnownIssuesType))
+
 	cmd.PersistentFlags().String(JiraURL, "", "Jira base URL")
 	viper.BindPFlag(JiraURL, cmd.PersistentFlags().Lookup(JiraURL))
 
+	cmd.PersistentFlags().String(JiraFlavour, "cloud", "Jira flavour: cloud or server. With server, the REST API v2 of Jira Server/Data Center is used and jiraPassword is used as personal access token when jiraUsername is not set")
+	viper.BindPFlag(JiraFlavour, cmd.PersistentFlags().Lookup(JiraFlavour))
+
+	cmd.PersistentFlags().String(JiraBrowseURL, "", "Template of the Jira issue web URL, where {key} is replaced by the issue key. If not specified, jiraURL/browse/{key} is used")
+	viper.BindPFlag(JiraBrowseURL, cmd.PersistentFlags().Lookup(JiraBrowseURL))
+
+	cmd.PersistentFlags().String(JiraFixVersion, "", "Pattern of the Jira fixVersion of the release scope, where {version} is replaced by the version of the service. If specified, the issues of the fixVersion are added and cross-checked with the issues referenced by the commits")
+	viper.BindPFlag(JiraFixVersion, cmd.PersistentFlags().Lookup(JiraFixVersion))
+
+	cmd.PersistentFlags().Bool(JiraEpicRollUp, false, "if true, retrieve the epic of each Jira issue and group the features by epic")
+	viper.BindPFlag(JiraEpicRollUp, cmd.PersistentFlags().Lookup(JiraEpicRollUp))
+
+	cmd.PersistentFlags().String(JiraEpicLinkField, "", "Jira field, identified by id or name (e.g. Epic Link), holding the epic of the issues. If not specified, the parent field is used")
+	viper.BindPFlag(JiraEpicLinkField, cmd.PersistentFlags().Lookup(JiraEpicLinkField))
+
+	cmd.PersistentFlags().String(JiraFields, "", "List of mappings name:field separated by comma of the Jira fields, identified by id or name, added to the fields of the issue details")
+	viper.BindPFlag(JiraFields, cmd.PersistentFlags().Lookup(JiraFields))
+
 	cmd.PersistentFlags().String(JiraUsername, "", "Jira username with read REST API permissions")
 	viper.BindPFlag(JiraUsername, cmd.PersistentFlags().Lookup(JiraUsername))
 
 	cmd.PersistentFlags().String(JiraPassword, "", "Jira password/token with read REST API permissions")
 	viper.BindPFlag(JiraPassword, cmd.PersistentFlags().Lookup(JiraPassword))
 
-	cmd.PersistentFlags().String(JiraClosedFeatureFilter, "Story:GOLIVE,TECH TASK:Completata", "List of filters type:status that identify the closed features")
+	cmd.PersistentFlags().String(JiraClosedFeatureFilter, defaultJiraClosedFeatureFilter, "List of filters type:status that identify the closed features")
 	viper.BindPFlag(JiraClosedFeatureFilter, cmd.PersistentFlags().Lookup(JiraClosedFeatureFilter))
 
-	cmd.PersistentFlags().String(JiraFixedBugFilter, "BUG:FIXED,BUG:RELEASED", "List of filters type:status that identify the fixed bugs")
+	cmd.PersistentFlags().String(JiraFixedBugFilter, defaultJiraFixedBugFilter, "List of filters type:status that identify the fixed bugs")
 	viper.BindPFlag(JiraFixedBugFilter, cmd.PersistentFlags().Lookup(JiraFixedBugFilter))
 
-	cmd.PersistentFlags().String(JiraKnownIssuesJQL, "status not in (Done, RELEASED, Fixed, GOLIVE, Cancelled) AND issuetype in (Bug, \"TECH DEBT\")", "Jira JQL to retrieve the known issues")
+	cmd.PersistentFlags().String(JiraKnownIssuesJQL, defaultJiraKnownIssuesJQL, "Jira JQL to retrieve the known issues. The JQL is a template evaluated for each service with its fields, e.g. {{ .version }}")
 	viper.BindPFlag(JiraKnownIssuesJQL, cmd.PersistentFlags().Lookup(JiraKnownIssuesJQL))
+
+	cmd.PersistentFlags().Int(JiraSearchPageSize, 1000, "Max number of issues requested for each page of the Jira searches")
+	viper.BindPFlag(JiraSearchPageSize, cmd.PersistentFlags().Lookup(JiraSearchPageSize))
+
+	cmd.PersistentFlags().Int(JiraKeysBatchSize, 100, "Max number of issue keys included in each Jira search")
+	viper.BindPFlag(JiraKeysBatchSize, cmd.PersistentFlags().Lookup(JiraKeysBatchSize))
 }
 
 func initConfig() {
@@ -165,6 +438,9 @@ func initConfig() {
 
 func addJiraOpt(label string, value string, opts *[]issuetrackers.JiraOpt, opt func(string, string) issuetrackers.JiraOpt) error {
 	fmt.Printf("using %s -> %s\n", label, value)
+	if value == "" {
+		return nil
+	}
 	filters := strings.Split(value, ",")
 	if len(filters) == 0 {
 		return fmt.Errorf("wrong format of %s, expected list type:status separated by coma", label)
@@ -180,48 +456,350 @@ func addJiraOpt(label string, value string, opts *[]issuetrackers.JiraOpt, opt f
 	return nil
 }
 
+// JiraConnection holds the connection details of a Jira site.
+type JiraConnection struct {
+	URL      string `yaml:"url" mapstructure:"url"`
+	Flavour  string `yaml:"flavour" mapstructure:"flavour"`
+	Username string `yaml:"username" mapstructure:"username"`
+	Password string `yaml:"password" mapstructure:"password"`
+}
+
+// JiraOptions holds the options of a Jira issues tracker.
+type JiraOptions struct {
+	ClosedFeatureFilter string      `yaml:"closedFeatureFilter" mapstructure:"closedFeatureFilter"`
+	FixedBugFilter      string      `yaml:"fixedBugFilter" mapstructure:"fixedBugFilter"`
+	KnownIssuesJQL      string      `yaml:"knownIssuesJQL" mapstructure:"knownIssuesJQL"`
+	BrowseURL           string      `yaml:"browseURL" mapstructure:"browseURL"`
+	FixVersion          string      `yaml:"fixVersion" mapstructure:"fixVersion"`
+	EpicRollUp          bool        `yaml:"epicRollUp" mapstructure:"epicRollUp"`
+	EpicLinkField       string      `yaml:"epicLinkField" mapstructure:"epicLinkField"`
+	Fields              []JiraField `yaml:"fields" mapstructure:"fields"`
+	SearchPageSize      int         `yaml:"searchPageSize" mapstructure:"searchPageSize"`
+	KeysBatchSize       int         `yaml:"keysBatchSize" mapstructure:"keysBatchSize"`
+}
+
+func getJiraConnection() JiraConnection {
+	return JiraConnection{
+		URL:      GetConfigString(JiraURL),
+		Flavour:  GetConfigString(JiraFlavour),
+		Username: GetConfigString(JiraUsername),
+		Password: GetConfigString(JiraPassword),
+	}
+}
+
+func getJiraOptions() (JiraOptions, error) {
+	fields, err := GetJiraFields()
+	if err != nil {
+		return JiraOptions{}, err
+	}
+
+	return JiraOptions{
+		ClosedFeatureFilter: GetConfigString(JiraClosedFeatureFilter),
+		FixedBugFilter:      GetConfigString(JiraFixedBugFilter),
+		KnownIssuesJQL:      GetConfigString(JiraKnownIssuesJQL),
+		BrowseURL:           GetConfigString(JiraBrowseURL),
+		FixVersion:          GetConfigString(JiraFixVersion),
+		EpicRollUp:          GetConfigBool(JiraEpicRollUp),
+		EpicLinkField:       GetConfigString(JiraEpicLinkField),
+		Fields:              fields,
+		SearchPageSize:      GetConfigInt(JiraSearchPageSize),
+		KeysBatchSize:       GetConfigInt(JiraKeysBatchSize),
+	}, nil
+}
+
+// validateJiraConnection checks the credentials required by the flavour, with
+// the names of the settings reported in the error.
+func validateJiraConnection(c JiraConnection, url, username, password string) error {
+	if issuetrackers.NormalizeFlavour(c.Flavour) == issuetrackers.ServerFlavour {
+		if c.URL == "" || c.Password == "" {
+			return fmt.Errorf("%w: %s and %s are required", errTrackerNotConfigured, url, password)
+		}
+	} else if c.URL == "" || c.Username == "" || c.Password == "" {
+		return fmt.Errorf("%w: %s, %s and %s are required", errTrackerNotConfigured, url, username, password)
+	}
+
+	return nil
+}
+
 func ConfigureJira() (*issuetrackers.Jira, error) {
-	if GetConfigString(JiraURL) == "" || GetConfigString(JiraUsername) == "" || GetConfigString(JiraPassword) == "" {
-		return nil, fmt.Errorf("jiraURL, jiraUsername and jiraPassword are required")
+	c := getJiraConnection()
+	if err := validateJiraConnection(c, JiraURL, JiraUsername, JiraPassword); err != nil {
+		return nil, err
+	}
+
+	o, err := getJiraOptions()
+	if err != nil {
+		return nil, err
 	}
 
+	return newJira(c, o)
+}
+
+func newJira(c JiraConnection, o JiraOptions) (*issuetrackers.Jira, error) {
 	var opts []issuetrackers.JiraOpt
-	addJiraOpt("jiraClosedFeatureFilter", GetConfigString(JiraClosedFeatureFilter), &opts, issuetrackers.WithClosedFeatureFilter)
-	addJiraOpt("jiraFixedBugFilter", GetConfigString(JiraFixedBugFilter), &opts, issuetrackers.WithFixedBugFilter)
-	fmt.Printf("using %s -> %s\n", "jiraKnownIssuesJQL", GetConfigString(JiraKnownIssuesJQL))
-	fmt.Printf("using %s -> %s\n", "jiraURL", GetConfigString(JiraURL))
-
-	opts = append(opts, issuetrackers.WithKnownIssueJql(GetConfigString(JiraKnownIssuesJQL)))
-	jiraTracker, err := issuetrackers.NewJira(
-		GetConfigString(JiraURL),
-		GetConfigString(JiraUsername),
-		GetConfigString(JiraPassword),
-		opts...,
-	)
+	if err := addJiraOpt("jiraClosedFeatureFilter", o.ClosedFeatureFilter, &opts, issuetrackers.WithClosedFeatureFilter); err != nil {
+		return nil, err
+	}
+	if err := addJiraOpt("jiraFixedBugFilter", o.FixedBugFilter, &opts, issuetrackers.WithFixedBugFilter); err != nil {
+		return nil, err
+	}
+	fmt.Printf("using %s -> %s\n", "jiraKnownIssuesJQL", o.KnownIssuesJQL)
+	fmt.Printf("using %s -> %s\n", "jiraURL", c.URL)
+	fmt.Printf("using %s -> %s\n", "jiraFlavour", c.Flavour)
+	fmt.Printf("using %s -> %s\n", "jiraBrowseURL", o.BrowseURL)
+
+	opts = append(opts, issuetrackers.WithKnownIssueJql(o.KnownIssuesJQL),
+		issuetrackers.WithSearchPageSize(o.SearchPageSize),
+		issuetrackers.WithKeysBatchSize(o.KeysBatchSize),
+		issuetrackers.WithBrowseURL(o.BrowseURL),
+		issuetrackers.WithFlavour(c.Flavour),
+		issuetrackers.WithFixVersion(o.FixVersion))
+
+	if o.EpicRollUp {
+		fmt.Printf("using %s -> %s\n", "jiraEpicLinkField", o.EpicLinkField)
+		opts = append(opts, issuetrackers.WithEpicRollUp(o.EpicLinkField))
+	}
+
+	for _, f := range o.Fields {
+		fmt.Printf("using %s -> %s:%s\n", "jiraFields", f.Name, f.Field)
+		opts = append(opts, issuetrackers.WithField(f.Name, f.Field))
+	}
+
+	jiraTracker, err := issuetrackers.NewJira(c.URL, c.Username, c.Password, opts...)
 
 	return &jiraTracker, err
 }
 
+// categoryRule associates the issues having the label, or the issue type, to the category.
+type categoryRule struct {
+	name     string
+	category entities.IssueCategory
+}
+
+// parseCategoryRules parses the items name:category of the setting key.
+func parseCategoryRules(key string, items []string) ([]categoryRule, error) {
+	var rules []categoryRule
+	for _, item := range items {
+		i := strings.LastIndex(item, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("wrong format of %s, expected list name:category separated by comma", key)
+		}
+		category, err := entities.ParseIssueCategory(strings.TrimSpace(item[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("wrong format of %s: %w", key, err)
+		}
+		rules = append(rules, categoryRule{name: item[:i], category: category})
+	}
+
+	return rules, nil
+}
+
+// parseDefaultCategory parses the category of the setting key, nil when not set.
+func parseDefaultCategory(key, value string) (*entities.IssueCategory, error) {
+	if value == "" {
+		return nil, nil
+	}
+	category, err := entities.ParseIssueCategory(value)
+	if err != nil {
+		return nil, fmt.Errorf("wrong format of %s: %w", key, err)
+	}
+
+	return &category, nil
+}
+
+func gitLabOpts(o GitOptions) ([]clients.GitLabOpt, error) {
+	opts := []clients.GitLabOpt{
+		clients.WithPageSize(o.PageSize),
+		clients.WithMaxPages(o.MaxPages),
+		clients.WithKnownIssuesLabels(o.KnownIssuesLabels),
+		clients.WithKnownIssuesMilestone(o.KnownIssuesMilestone),
+		clients.WithKnownIssuesType(o.KnownIssuesType),
+	}
+
+	fmt.Printf("using %s -> %s\n", GitLabelCategories, o.LabelCategories)
+	rules, err := parseCategoryRules(GitLabelCategories, splitList(o.LabelCategories))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		opts = append(opts, clients.WithLabelCategory(r.name, r.category))
+	}
+
+	fmt.Printf("using %s -> %s\n", GitDefaultCategory, o.DefaultCategory)
+	category, err := parseDefaultCategory(GitDefaultCategory, o.DefaultCategory)
+	if err != nil {
+		return nil, err
+	}
+	if category != nil {
+		opts = append(opts, clients.WithDefaultCategory(*category))
+	}
+
+	return opts, nil
+}
+
+// GitHubOptions holds the issue settings of a GitHub issues tracker, with the
+// categories expressed as list name:category separated by comma.
+type GitHubOptions struct {
+	Repository           string   `yaml:"repository" mapstructure:"repository"`
+	LabelCategories      string   `yaml:"labelCategories" mapstructure:"labelCategories"`
+	IssueTypeCategories  string   `yaml:"issueTypeCategories" mapstructure:"issueTypeCategories"`
+	DefaultCategory      string   `yaml:"defaultCategory" mapstructure:"defaultCategory"`
+	KnownIssuesLabels    []string `yaml:"knownIssuesLabels" mapstructure:"knownIssuesLabels"`
+	KnownIssuesMilestone string   `yaml:"knownIssuesMilestone" mapstructure:"knownIssuesMilestone"`
+	KnownIssuesType      string   `yaml:"knownIssuesType" mapstructure:"knownIssuesType"`
+}
+
+func getGitHubOptions() GitHubOptions {
+	return getGitOptions().gitHubOptions()
+}
+
+// gitHubOptions returns the issue settings of the git options.
+func (o GitOptions) gitHubOptions() GitHubOptions {
+	return GitHubOptions{
+		LabelCategories:      o.LabelCategories,
+		IssueTypeCategories:  o.IssueTypeCategories,
+		DefaultCategory:      o.DefaultCategory,
+		KnownIssuesLabels:    o.KnownIssuesLabels,
+		KnownIssuesMilestone: o.KnownIssuesMilestone,
+		KnownIssuesType:      o.KnownIssuesType,
+	}
+}
+
+func gitHubOpts(o GitHubOptions) ([]clients.GitHubOpt, error) {
+	opts := []clients.GitHubOpt{
+		clients.WithGitHubRepository(o.Repository),
+		clients.WithGitHubKnownIssues(entities.KnownIssuesFilter{
+			Labels:    o.KnownIssuesLabels,
+			Milestone: o.KnownIssuesMilestone,
+			IssueType: o.KnownIssuesType,
+		}),
+	}
+
+	fmt.Printf("using %s -> %s\n", GitLabelCategories, o.LabelCategories)
+	rules, err := parseCategoryRules(GitLabelCategories, splitList(o.LabelCategories))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		opts = append(opts, clients.WithGitHubLabelCategory(r.name, r.category))
+	}
+
+	fmt.Printf("using %s -> %s\n", GitIssueTypeCategories, o.IssueTypeCategories)
+	rules, err = parseCategoryRules(GitIssueTypeCategories, splitList(o.IssueTypeCategories))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		opts = append(opts, clients.WithIssueTypeCategory(r.name, r.category))
+	}
+
+	fmt.Printf("using %s -> %s\n", GitDefaultCategory, o.DefaultCategory)
+	category, err := parseDefaultCategory(GitDefaultCategory, o.DefaultCategory)
+	if err != nil {
+		return nil, err
+	}
+	if category != nil {
+		opts = append(opts, clients.WithGitHubDefaultCategory(*category))
+	}
+
+	return opts, nil
+}
+
+func newRepoTracker(provider, URL, token string, o GitOptions) (entities.RepoTracker, error) {
+	provider = strings.ToLower(provider)
+	fmt.Printf("using %s -> %s\n", "gitProvider", provider)
+
+	switch provider {
+	case "", "gitlab":
+		if URL == "" || token == "" {
+			return nil, fmt.Errorf("gitURL and gitToken are required")
+		}
+		fmt.Printf("using %s -> %s\n", "gitURL", URL)
+
+		opts, err := gitLabOpts(o)
+		if err != nil {
+			return nil, err
+		}
+
+		return clients.NewGitLab(URL, token, opts...)
+	case "github":
+		if token == "" {
+			return nil, fmt.Errorf("gitToken is required")
+		}
+		fmt.Printf("using %s -> %s\n", "gitURL", URL)
+
+		opts, err := gitHubOpts(o.gitHubOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		return clients.NewGitHub(URL, token, opts...)
+	case "local":
+		fmt.Printf("using %s -> %s\n", "gitURL", URL)
+
+		return clients.NewLocal(URL)
+	default:
+		return nil, fmt.Errorf("unsupported gitProvider %q, expected gitlab, github or local", provider)
+	}
+}
+
 func ConfigureRepoTracker() (entities.RepoTracker, error) {
-	if GetConfigString(GitURL) == "" || GetConfigString(GitToken) == "" {
-		return nil, fmt.Errorf("gitURL and gitToken are required")
+	return newRepoTracker(GetConfigString(GitProvider), GetConfigString(GitURL), GetConfigString(GitToken), getGitOptions())
+}
+
+// ConfigureGitProfiles returns the repo trackers of the configured git profiles indexed by name.
+func ConfigureGitProfiles() (map[string]entities.RepoTracker, error) {
+	profiles, err := GetGitProfiles()
+	if err != nil {
+		return nil, err
 	}
-	fmt.Printf("using %s -> %s\n", "gitURL", GetConfigString(GitURL))
 
-	git, err := clients.NewGitLab(GetConfigString(GitURL), GetConfigString(GitToken))
+	trackers := map[string]entities.RepoTracker{}
+	for _, p := range profiles {
+		if p.Name == "" {
+			return nil, fmt.Errorf("git profile name is required")
+		}
+		if _, ok := trackers[p.Name]; ok {
+			return nil, fmt.Errorf("git profile %q defined more than once", p.Name)
+		}
+
+		fmt.Printf("using git profile %s\n", p.Name)
+		t, err := newRepoTracker(p.Provider, p.URL, p.Token, p.GitOptions.withDefaults(getGitOptions()))
+		if err != nil {
+			return nil, fmt.Errorf("git profile %q: %w", p.Name, err)
+		}
+		trackers[p.Name] = t
+	}
 
-	return git, err
+	return trackers, nil
 }
 
-func ConfigureRepoService(repoClient entities.RepoClient) (entities.RepoService, error) {
+func ConfigureRepoService(repoClient entities.RepoClient, issuePatterns []parsers.IssuePattern) (entities.RepoService, error) {
 	fmt.Printf("using %s -> %s\n", CustomCommitPattern, GetConfigString(CustomCommitPattern))
 	fmt.Printf("using %s -> %v\n", WithCCWithoutScope, GetConfigString(WithCCWithoutScope))
 	fmt.Printf("using %s -> %v\n", GitMRBranch, GetConfigString(GitMRBranch))
+	fmt.Printf("using %s -> %q\n", ScopeSeparators, GetConfigString(ScopeSeparators))
+	fmt.Printf("using %s -> %s\n", CommitTypeCategories, GetConfigString(CommitTypeCategories))
 
-	repoParser, err := repo.New(repoClient, GetIssuePatterns(),
+	opts := []repo.RepoParserOpt{
 		repo.WithDefaultMRBranch(GetConfigString(GitMRBranch)),
 		repo.WithCustomPattern(GetConfigString(CustomCommitPattern)),
-		repo.WithKeepCCWithoutScope(GetConfigBool(WithCCWithoutScope)))
+		repo.WithKeepCCWithoutScope(GetConfigBool(WithCCWithoutScope)),
+		repo.WithScopeSeparators(GetConfigString(ScopeSeparators)),
+	}
+	for _, item := range getConfigList(CommitTypeCategories) {
+		t, c, found := strings.Cut(item, ":")
+		if !found || strings.TrimSpace(t) == "" {
+			return nil, fmt.Errorf("wrong format of %s, expected list type:category separated by comma", CommitTypeCategories)
+		}
+		category, err := entities.ParseCommitCategory(strings.TrimSpace(c))
+		if err != nil {
+			return nil, fmt.Errorf("wrong format of %s: %w", CommitTypeCategories, err)
+		}
+		opts = append(opts, repo.WithTypeCategory(strings.TrimSpace(t), category))
+	}
+
+	repoParser, err := repo.New(repoClient, issuePatterns, opts...)
 
 	return repoParser, err
 }
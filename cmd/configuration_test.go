@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/happyagosmith/jig/internal/entities"
+	"github.com/happyagosmith/jig/internal/issuetrackers"
+	"github.com/happyagosmith/jig/internal/repo/clients"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,3 +41,276 @@ func TestGetIssuePatternsFromConfigFile(t *testing.T) {
 		assert.Equal(t, `#(\d+)`, patterns[2].Pattern)
 	}
 }
+
+func TestConfigureGitProfiles(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigFile("testdata/config-profiles.yaml")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	profiles, err := GetGitProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []GitProfile{
+		{Name: "selfManaged", Provider: "gitlab", URL: "https://gitlab.example.com", Token: "gitlabToken", GitOptions: GitOptions{PageSize: 20, KnownIssuesLabels: []string{"known-issue"}}},
+		{Name: "public", Provider: "github", Token: "githubToken"},
+	}, profiles)
+
+	trackers, err := ConfigureGitProfiles()
+	require.NoError(t, err)
+	assert.Len(t, trackers, 2)
+	assert.IsType(t, clients.Git{}, trackers["selfManaged"])
+	assert.IsType(t, clients.GitHub{}, trackers["public"])
+}
+
+func TestConfigureGitProfilesOptions(t *testing.T) {
+	var gitLabLabels []string
+	gitLabSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gitLabLabels = append(gitLabLabels, r.URL.Query().Get("labels"))
+		fmt.Fprint(w, `[{"id": 11, "iid": 1, "title": "known", "state": "opened", "labels": ["incident"]}]`)
+	}))
+	defer gitLabSrv.Close()
+
+	var gitHubQuery string
+	gitHubSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gitHubQuery = r.URL.Query().Get("q")
+		fmt.Fprint(w, `{"items": [{"number": 1, "title": "known", "state": "open", "labels": [{"name": "incident"}]}]}`)
+	}))
+	defer gitHubSrv.Close()
+
+	viper.Reset()
+	viper.Set(GitKnownIssuesLabels, "global-known")
+	viper.Set(GitLabelCategories, "defect:FIXED_BUG")
+	viper.Set(GitProfiles, []map[string]any{
+		{"name": "internal", "provider": "gitlab", "url": gitLabSrv.URL, "token": "gitlabToken", "knownIssuesLabels": []string{"gl-known"}, "labelCategories": "incident:FIXED_BUG"},
+		{"name": "public", "provider": "github", "url": gitHubSrv.URL, "token": "githubToken", "knownIssuesLabels": []string{"gh-known"}, "knownIssuesMilestone": "2.0"},
+		{"name": "fallback", "provider": "gitlab", "url": gitLabSrv.URL, "token": "gitlabToken"},
+	})
+
+	trackers, err := ConfigureGitProfiles()
+	require.NoError(t, err)
+	require.Len(t, trackers, 3)
+
+	gitLabRepo := &entities.EnrichedRepo{Repo: entities.Repo{ID: "1"}}
+	issues, err := trackers["internal"].GetKnownIssues(context.Background(), gitLabRepo)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, entities.FIXED_BUG, issues[0].Category)
+
+	issues, err = trackers["fallback"].GetKnownIssues(context.Background(), gitLabRepo)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, entities.OTHER, issues[0].Category)
+	assert.Equal(t, []string{"gl-known", "global-known"}, gitLabLabels)
+
+	issues, err = trackers["public"].GetKnownIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "my/repo"}})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, entities.CLOSED_FEATURE, issues[0].Category)
+	assert.Equal(t, `repo:my/repo is:issue is:open label:"gh-known" milestone:"2.0"`, gitHubQuery)
+	viper.Reset()
+}
+
+func TestGetJiraFields(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigFile("testdata/config-jira-fields.yaml")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	fields, err := GetJiraFields()
+	require.NoError(t, err)
+	assert.Equal(t, []JiraField{
+		{Name: "releaseNote", Field: "Release Note"},
+		{Name: "priority", Field: "priority"},
+	}, fields)
+
+	viper.Reset()
+	viper.Set(JiraFields, "releaseNote:customfield_10010, components:components")
+
+	fields, err = GetJiraFields()
+	require.NoError(t, err)
+	assert.Equal(t, []JiraField{
+		{Name: "releaseNote", Field: "customfield_10010"},
+		{Name: "components", Field: "components"},
+	}, fields)
+}
+
+func TestConfigureHTTPTrackers(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigFile("testdata/config-http-trackers.yaml")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	configs, err := GetHTTPTrackers()
+	require.NoError(t, err)
+	assert.Equal(t, []HTTPTracker{
+		{
+			Name:     "silk",
+			IssueURL: "https://silk.example.com/api/issues/{key}",
+			Headers:  map[string]string{"authorization": "Bearer silkToken"},
+			Fields:   issuetrackers.HTTPFields{Summary: "$.fields.title", URL: "$.links.self"},
+			Categories: []HTTPCategoryRule{
+				{Type: "story", Status: "done", Category: "CLOSED_FEATURE"},
+				{Type: "defect", Category: "FIXED_BUG"},
+			},
+			DefaultCategory: "OTHER",
+			Timeout:         10,
+		},
+	}, configs)
+
+	trackers, err := ConfigureHTTPTrackers()
+	require.NoError(t, err)
+	require.Len(t, trackers, 1)
+	assert.Equal(t, "SILK", trackers[0].label)
+	assert.IsType(t, issuetrackers.HTTP{}, trackers[0].it)
+
+	viper.Set(HTTPTrackers, []map[string]any{{"name": "silk", "issueURL": "https://silk.example.com/{key}", "defaultCategory": "UNKNOWN"}})
+	_, err = ConfigureHTTPTrackers()
+	assert.Error(t, err)
+	viper.Reset()
+}
+
+func TestConfigureIssueTrackers(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigFile("testdata/config-issue-trackers.yaml")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	trackers, err := ConfigureIssueTrackers()
+	require.NoError(t, err)
+	require.Len(t, trackers, 4)
+	assert.Equal(t, "JIRA", trackers[0].label)
+	assert.IsType(t, &issuetrackers.Jira{}, trackers[0].it)
+	assert.Equal(t, "PARTNER", trackers[1].label)
+	assert.IsType(t, unconfiguredTracker{}, trackers[1].it)
+	assert.Equal(t, "SILK", trackers[2].label)
+	assert.IsType(t, issuetrackers.HTTP{}, trackers[2].it)
+	assert.Equal(t, "GH", trackers[3].label)
+	assert.IsType(t, clients.GitHub{}, trackers[3].it)
+
+	_, err = trackers[1].it.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"PRT-1"})
+	assert.ErrorIs(t, err, errTrackerNotConfigured)
+	knownIssues, err := trackers[1].it.GetKnownIssues(context.Background(), &entities.EnrichedRepo{})
+	assert.NoError(t, err)
+	assert.Nil(t, knownIssues)
+
+	gitTracker := clients.NewProfileTracker(nil, nil)
+	all := withDefaultTrackers(trackers, gitTracker, GetIssuePatterns())
+	require.Len(t, all, 6)
+	assert.Equal(t, "GIT", all[4].label)
+	assert.Equal(t, "OTHER", all[5].label)
+	assert.Nil(t, all[5].it)
+
+	viper.Set(IssueTrackers, []map[string]any{{"name": "jira", "type": "jira", "connection": map[string]any{"url": "https://jira.example.com", "token": "x"}}})
+	_, err = ConfigureIssueTrackers()
+	assert.Error(t, err)
+
+	viper.Set(IssueTrackers, []map[string]any{{"name": "youtrack", "type": "youtrack"}})
+	_, err = ConfigureIssueTrackers()
+	assert.Error(t, err)
+	viper.Reset()
+}
+
+func TestConfigureIssueTrackersWithoutJiraCredentials(t *testing.T) {
+	viper.Reset()
+
+	trackers, err := ConfigureIssueTrackers()
+	require.NoError(t, err)
+	require.Len(t, trackers, 1)
+	assert.Equal(t, "JIRA", trackers[0].label)
+
+	_, err = trackers[0].it.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"AAA-1"})
+	assert.ErrorIs(t, err, errTrackerNotConfigured)
+
+	viper.Set(JiraURL, "https://jira.example.com")
+	viper.Set(JiraFlavour, "DataCenter")
+	viper.Set(JiraPassword, "token")
+	trackers, err = ConfigureIssueTrackers()
+	require.NoError(t, err)
+	require.Len(t, trackers, 1)
+	assert.IsType(t, &issuetrackers.Jira{}, trackers[0].it)
+	viper.Reset()
+}
+
+func TestConfigureIssueTrackersWithLegacySettings(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigFile("testdata/config-issue-trackers.yaml")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	viper.Set(JiraURL, "https://jira.example.com")
+	_, err = ConfigureIssueTrackers()
+	assert.ErrorContains(t, err, "jiraURL can't be used together with issueTrackers")
+
+	viper.Set(JiraURL, "")
+	viper.Set(HTTPTrackers, []map[string]any{{"name": "silk", "issueURL": "https://silk.example.com/api/issues/{key}"}})
+	_, err = ConfigureIssueTrackers()
+	assert.ErrorContains(t, err, "httpTrackers can't be used together with issueTrackers")
+	viper.Reset()
+}
+
+func TestJiraIssueTrackerSiteOptions(t *testing.T) {
+	var gotJQLs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotJQLs = append(gotJQLs, r.URL.Query().Get("jql"))
+		fmt.Fprint(w, `{"issues": []}`)
+	}))
+	defer srv.Close()
+
+	viper.Reset()
+	viper.Set(JiraKnownIssuesJQL, "labels = legacy")
+	viper.Set(JiraFixVersion, "legacy-{version}")
+	viper.Set(JiraClosedFeatureFilter, "wrong format")
+	viper.Set(JiraFields, "wrong format")
+	viper.Set(JiraEpicRollUp, true)
+
+	it, err := newJiraIssueTracker(IssueTrackerConfig{
+		Name:       "partner",
+		Type:       "jira",
+		Connection: map[string]any{"url": srv.URL, "username": "jiraUsername", "password": "jiraPassword"},
+	})
+	require.NoError(t, err)
+
+	repo := &entities.EnrichedRepo{Repo: entities.Repo{Project: "PRT", ToTag: "1.0.0"}}
+	scope, err := it.(entities.ScopeTracker).GetScopeIssues(context.Background(), repo)
+	assert.NoError(t, err)
+	assert.Nil(t, scope)
+
+	_, err = it.GetKnownIssues(context.Background(), repo)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{defaultJiraKnownIssuesJQL + " and project = \"PRT\""}, gotJQLs)
+
+	gotJQLs = nil
+	_, err = it.GetIssues(context.Background(), repo, []string{"PRT-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"issue in (PRT-1)"}, gotJQLs, "the epics are not rolled up")
+	viper.Reset()
+}
+
+func TestGetCategories(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigFile("testdata/config-categories.yaml")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	categories, err := GetCategories()
+	require.NoError(t, err)
+	assert.Equal(t, []CategoryConfig{
+		{Name: "enhancements", IssueTypes: []string{"Improvement:Done"}, Labels: []string{"improvement"}, CommitTypes: []string{"perf"}, CommitCategories: []string{"IMPROVEMENT"}},
+		{Name: "technical", Labels: []string{"type::tech*"}, CommitTypes: []string{"refactor", "build"}, Bump: "patch"},
+	}, categories)
+
+	opts, err := ConfigureCategories()
+	require.NoError(t, err)
+	assert.Len(t, opts, 2)
+
+	viper.Set(Categories, []map[string]any{{"name": "technical", "commitCategories": []string{"CHORE"}}})
+	_, err = ConfigureCategories()
+	assert.Error(t, err)
+	viper.Reset()
+}
@@ -8,26 +8,65 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/happyagosmith/jig/internal/entities"
 	"github.com/happyagosmith/jig/internal/filehandler/model"
+	"github.com/happyagosmith/jig/internal/parsers"
+	"github.com/happyagosmith/jig/internal/repo/clients"
 	"github.com/spf13/cobra"
 )
 
-func EnrichModel(cmd *cobra.Command, b []byte) []byte {
-	jiraTracker, err := ConfigureJira()
+// configureRepos returns the model options setting the repo services of the
+// default git configuration and of the git profiles, parsing the commits with
+// the issue patterns, together with the issues tracker resolving the git issues
+// against the repo tracker of each service.
+func configureRepos(cmd *cobra.Command, issuePatterns []parsers.IssuePattern) ([]model.ModelOpt, entities.IssuesTracker) {
+	profileTrackers, err := ConfigureGitProfiles()
 	CheckErr(cmd, err)
 
+	var opts []model.ModelOpt
+	var defaultTracker entities.IssuesTracker
+
 	repoTracker, err := ConfigureRepoTracker()
+	if err != nil && len(profileTrackers) == 0 {
+		CheckErr(cmd, err)
+	}
+	if err != nil {
+		fmt.Printf("default git configuration not used: %v\n", err)
+	} else {
+		repoService, err := ConfigureRepoService(repoTracker, issuePatterns)
+		CheckErr(cmd, err)
+		opts = append(opts, model.WithRepoService(repoService))
+		defaultTracker = repoTracker
+	}
+
+	trackers := make(map[string]entities.IssuesTracker, len(profileTrackers))
+	for name, t := range profileTrackers {
+		repoService, err := ConfigureRepoService(t, issuePatterns)
+		CheckErr(cmd, err)
+		opts = append(opts, model.WithGitProfile(name, repoService))
+		trackers[name] = t
+	}
+
+	return opts, clients.NewProfileTracker(defaultTracker, trackers)
+}
+
+func EnrichModel(cmd *cobra.Command, b []byte) []byte {
+	trackers, err := ConfigureIssueTrackers()
 	CheckErr(cmd, err)
 
-	repoService, err := ConfigureRepoService(repoTracker)
+	// the links to the issues take precedence over the keys they include
+	issuePatterns := append(urlIssuePatterns(trackers), GetIssuePatterns()...)
+
+	opts, gitTracker := configureRepos(cmd, issuePatterns)
+	for _, t := range withDefaultTrackers(trackers, gitTracker, GetIssuePatterns()) {
+		opts = append(opts, model.WithIssueTracker(t.label, t.it))
+	}
+
+	categoryOpts, err := ConfigureCategories()
 	CheckErr(cmd, err)
+	opts = append(opts, categoryOpts...)
 
-	model, err := model.New(b,
-		model.WithRepoService(repoService),
-		model.WithIssueTracker("JIRA", jiraTracker),
-		model.WithIssueTracker("GIT", repoTracker),
-		model.WithIssueTracker("SILK", nil),
-	)
+	model, err := model.New(b, opts...)
 	CheckErr(cmd, err)
 
 	err = model.EnrichWithRepos()
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/happyagosmith/jig/cmd"
@@ -67,6 +68,9 @@ func TestEnrich(t *testing.T) {
 						t.Fatal(err)
 					}
 					w.Write(b)
+				} else if strings.HasPrefix(r.URL.Path, "/api/v4/projects/123/merge_requests/") {
+					w.WriteHeader(200)
+					w.Write([]byte("[]"))
 				} else if r.URL.Path == "/api/v4/projects/123/merge_requests" {
 					w.WriteHeader(200)
 					b, err := os.ReadFile(tt.mockgitmr)
@@ -100,7 +104,7 @@ func TestEnrich(t *testing.T) {
 
 			assert.NoError(t, err)
 			assert.NotNil(t, gotJiraRequest)
-			assert.Equal(t, 3, len(gotGitRequest))
+			assert.Equal(t, 5, len(gotGitRequest))
 
 			assertEqualContentFile(t, tt.wantModel, modelFile.Name())
 		})
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/happyagosmith/jig/cmd"
@@ -80,6 +81,9 @@ func TestGenerate(t *testing.T) {
 						t.Fatal(err)
 					}
 					w.Write(b)
+				} else if strings.HasPrefix(r.URL.Path, "/api/v4/projects/123/merge_requests/") {
+					w.WriteHeader(200)
+					w.Write([]byte("[]"))
 				} else if r.URL.Path == "/api/v4/projects/123/merge_requests" {
 					w.WriteHeader(200)
 					b, err := os.ReadFile(*tt.mockgitmr)
@@ -116,7 +120,7 @@ func TestGenerate(t *testing.T) {
 
 			assert.NoError(t, err)
 			if tt.mockgitcommits != nil && tt.mockgitissues != nil {
-				assert.Equal(t, 3, len(gotGitRequest))
+				assert.Equal(t, 5, len(gotGitRequest))
 			}
 
 			if tt.mockjira != nil {
@@ -79,13 +79,8 @@ services:
 			b, err := fl.GetFile(modelPath)
 			CheckErr(cmd, err)
 
-			repoClient, err := ConfigureRepoTracker()
-			CheckErr(cmd, err)
-
-			repoService, err := ConfigureRepoService(repoClient)
-			CheckErr(cmd, err)
-
-			m, err := model.New(b, model.WithRepoService(repoService))
+			opts, _ := configureRepos(cmd, GetIssuePatterns())
+			m, err := model.New(b, opts...)
 			CheckErr(cmd, err)
 
 			err = m.UpdateWithReposVersions(filepath.Dir(modelPath))
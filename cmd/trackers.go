@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/happyagosmith/jig/internal/entities"
+	"github.com/happyagosmith/jig/internal/issuetrackers"
+	"github.com/happyagosmith/jig/internal/parsers"
+	"github.com/happyagosmith/jig/internal/repo/clients"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// IssueTrackerConfig declares an issues tracker of one of the registered types.
+// The issue patterns reference the tracker by name, so several trackers of the
+// same type, e.g. two Jira sites, can be used together.
+type IssueTrackerConfig struct {
+	Name       string         `yaml:"name" mapstructure:"name"`
+	Type       string         `yaml:"type" mapstructure:"type"`
+	Connection map[string]any `yaml:"connection" mapstructure:"connection"`
+	Options    map[string]any `yaml:"options" mapstructure:"options"`
+}
+
+func GetIssueTrackers() ([]IssueTrackerConfig, error) {
+	var trackers []IssueTrackerConfig
+	if err := viper.UnmarshalKey(IssueTrackers, &trackers); err != nil {
+		return nil, fmt.Errorf("error unmarshaling issue trackers: %w", err)
+	}
+
+	return trackers, nil
+}
+
+// issueTrackerFactory builds the issues tracker declared by the config.
+type issueTrackerFactory func(c IssueTrackerConfig) (entities.IssuesTracker, error)
+
+var issueTrackerFactories = map[string]issueTrackerFactory{
+	"jira":   newJiraIssueTracker,
+	"http":   newHTTPIssueTracker,
+	"github": newGitHubIssueTracker,
+}
+
+// errTrackerNotConfigured is returned when the connection details of a tracker
+// are missing. The run fails only if issues of the tracker are referenced.
+var errTrackerNotConfigured = errors.New("issues tracker not configured")
+
+// unconfiguredTracker replaces a tracker without connection details, failing
+// only when the issues of the tracker have to be retrieved.
+type unconfiguredTracker struct {
+	err error
+}
+
+func (t unconfiguredTracker) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, keys []string) ([]entities.Issue, error) {
+	return nil, t.err
+}
+
+func (t unconfiguredTracker) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
+	fmt.Printf("known issues not retrieved: %v\n", t.err)
+	return nil, nil
+}
+
+// namedTracker is an issues tracker with the label used by the issue patterns.
+type namedTracker struct {
+	label string
+	it    entities.IssuesTracker
+}
+
+// decodeConfig decodes the connection or the options of a tracker into out,
+// failing on the settings not supported by the tracker type.
+func decodeConfig(input map[string]any, out any) error {
+	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		ErrorUnused:      true,
+		Result:           out,
+	})
+	if err != nil {
+		return err
+	}
+
+	return d.Decode(input)
+}
+
+// newJiraIssueTracker builds a Jira tracker from its own connection and
+// options, ignoring the jira settings. The filters and the known issues JQL
+// not set take their default values, while the other options are not set.
+func newJiraIssueTracker(c IssueTrackerConfig) (entities.IssuesTracker, error) {
+	var conn JiraConnection
+	if err := decodeConfig(c.Connection, &conn); err != nil {
+		return nil, fmt.Errorf("invalid connection: %w", err)
+	}
+	if err := validateJiraConnection(conn, "url", "username", "password"); err != nil {
+		return nil, err
+	}
+
+	o := JiraOptions{
+		ClosedFeatureFilter: defaultJiraClosedFeatureFilter,
+		FixedBugFilter:      defaultJiraFixedBugFilter,
+		KnownIssuesJQL:      defaultJiraKnownIssuesJQL,
+	}
+	if err := decodeConfig(c.Options, &o); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	return newJira(conn, o)
+}
+
+// newGitHubIssueTracker builds a GitHub tracker. The options not set default to
+// the value of the git settings.
+func newGitHubIssueTracker(c IssueTrackerConfig) (entities.IssuesTracker, error) {
+	var conn struct {
+		URL   string `mapstructure:"url"`
+		Token string `mapstructure:"token"`
+	}
+	if err := decodeConfig(c.Connection, &conn); err != nil {
+		return nil, fmt.Errorf("invalid connection: %w", err)
+	}
+	if conn.Token == "" {
+		return nil, fmt.Errorf("%w: token is required", errTrackerNotConfigured)
+	}
+
+	o := getGitHubOptions()
+	if err := decodeConfig(c.Options, &o); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	opts, err := gitHubOpts(o)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.NewGitHub(conn.URL, conn.Token, opts...)
+}
+
+// newHTTPIssueTracker builds a http tracker, with the URLs and the headers set
+// in the connection and the other settings in the options.
+func newHTTPIssueTracker(c IssueTrackerConfig) (entities.IssuesTracker, error) {
+	var conn struct {
+		IssueURL  string            `mapstructure:"issueURL"`
+		BatchURL  string            `mapstructure:"batchURL"`
+		BrowseURL string            `mapstructure:"browseURL"`
+		Headers   map[string]string `mapstructure:"headers"`
+	}
+	if err := decodeConfig(c.Connection, &conn); err != nil {
+		return nil, fmt.Errorf("invalid connection: %w", err)
+	}
+
+	t := HTTPTracker{Name: c.Name, IssueURL: conn.IssueURL, BatchURL: conn.BatchURL, BrowseURL: conn.BrowseURL, Headers: conn.Headers}
+	if err := decodeConfig(c.Options, &t); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	return newHTTPTracker(t)
+}
+
+func newIssueTracker(c IssueTrackerConfig) (entities.IssuesTracker, error) {
+	factory, ok := issueTrackerFactories[strings.ToLower(c.Type)]
+	if !ok {
+		types := slices.Sorted(maps.Keys(issueTrackerFactories))
+		return nil, fmt.Errorf("unsupported type %q, expected %s", c.Type, strings.Join(types, " or "))
+	}
+
+	it, err := factory(c)
+	if errors.Is(err, errTrackerNotConfigured) {
+		fmt.Printf("issues tracker %s not configured: %v\n", c.Name, err)
+		return unconfiguredTracker{err: fmt.Errorf("issues tracker %q: %w", c.Name, err)}, nil
+	}
+
+	return it, err
+}
+
+// ConfigureIssueTrackers returns the issues trackers declared in issueTrackers,
+// in order. When none is declared, the Jira tracker of the jira settings and
+// the trackers of httpTrackers are returned.
+func ConfigureIssueTrackers() ([]namedTracker, error) {
+	configs, err := GetIssueTrackers()
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return configureDefaultIssueTrackers()
+	}
+	if err := checkLegacyIssueTrackers(); err != nil {
+		return nil, err
+	}
+
+	var trackers []namedTracker
+	for _, c := range configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("issues tracker name is required")
+		}
+		label := strings.ToUpper(c.Name)
+		if slices.ContainsFunc(trackers, func(t namedTracker) bool { return t.label == label }) {
+			return nil, fmt.Errorf("issues tracker %q defined more than once", c.Name)
+		}
+
+		fmt.Printf("using issues tracker %s of type %s\n", c.Name, c.Type)
+		it, err := newIssueTracker(c)
+		if err != nil {
+			return nil, fmt.Errorf("issues tracker %q: %w", c.Name, err)
+		}
+		trackers = append(trackers, namedTracker{label: label, it: it})
+	}
+
+	return trackers, nil
+}
+
+// checkLegacyIssueTrackers fails when the Jira connection of the jira settings
+// or httpTrackers are configured together with issueTrackers, as they would be
+// ignored.
+func checkLegacyIssueTrackers() error {
+	var legacy []string
+	for _, k := range []string{JiraURL, JiraUsername, JiraPassword} {
+		if GetConfigString(k) != "" {
+			legacy = append(legacy, k)
+		}
+	}
+	httpTrackers, err := GetHTTPTrackers()
+	if err != nil {
+		return err
+	}
+	if len(httpTrackers) > 0 {
+		legacy = append(legacy, HTTPTrackers)
+	}
+	if len(legacy) > 0 {
+		return fmt.Errorf("%s can't be used together with %s: declare the issues trackers in %s", strings.Join(legacy, ", "), IssueTrackers, IssueTrackers)
+	}
+
+	return nil
+}
+
+func configureDefaultIssueTrackers() ([]namedTracker, error) {
+	var jiraTracker entities.IssuesTracker
+	jira, err := ConfigureJira()
+	switch {
+	case errors.Is(err, errTrackerNotConfigured):
+		fmt.Printf("jira not configured: %v\n", err)
+		jiraTracker = unconfiguredTracker{err: fmt.Errorf("jira: %w", err)}
+	case err != nil:
+		return nil, err
+	default:
+		jiraTracker = jira
+	}
+
+	httpTrackers, err := ConfigureHTTPTrackers()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]namedTracker{{label: "JIRA", it: jiraTracker}}, httpTrackers...), nil
+}
+
+// urlIssuePatterns returns the patterns of the links to the issues of the
+// trackers exposing the template of the issue web URL.
+func urlIssuePatterns(trackers []namedTracker) []parsers.IssuePattern {
+	var patterns []parsers.IssuePattern
+	for _, t := range trackers {
+		bt, ok := t.it.(entities.BrowsableTracker)
+		if !ok || bt.IssueURLTemplate() == "" {
+			continue
+		}
+		p := parsers.URLIssuePattern(t.label, bt.IssueURLTemplate())
+		fmt.Printf("using issue links pattern %s -> %s\n", t.label, p.Pattern)
+		patterns = append(patterns, p)
+	}
+
+	return patterns
+}
+
+// withDefaultTrackers adds the git tracker, when no tracker named git is
+// declared, and a tracker without implementation for each issue tracker of the
+// issue patterns not declared, so that its issues get the commit details only.
+func withDefaultTrackers(trackers []namedTracker, gitTracker entities.IssuesTracker, patterns []parsers.IssuePattern) []namedTracker {
+	has := func(label string) bool {
+		return slices.ContainsFunc(trackers, func(t namedTracker) bool { return t.label == label })
+	}
+
+	if !has("GIT") {
+		trackers = append(trackers, namedTracker{label: "GIT", it: gitTracker})
+	}
+	for _, p := range patterns {
+		if label := strings.ToUpper(p.IssueTracker); label != "" && !has(label) {
+			fmt.Printf("no issues tracker configured for %s\n", p.IssueTracker)
+			trackers = append(trackers, namedTracker{label: label})
+		}
+	}
+
+	return trackers
+}
+
+// HTTPTracker configures an issues tracker exposing a REST API returning JSON,
+// registered with its name as issue tracker of the issue patterns.
+type HTTPTracker struct {
+	Name            string                   `yaml:"name" mapstructure:"name"`
+	IssueURL        string                   `yaml:"issueURL" mapstructure:"issueURL"`
+	BatchURL        string                   `yaml:"batchURL" mapstructure:"batchURL"`
+	IssuesPath      string                   `yaml:"issuesPath" mapstructure:"issuesPath"`
+	BatchSize       int                      `yaml:"batchSize" mapstructure:"batchSize"`
+	Headers         map[string]string        `yaml:"headers" mapstructure:"headers"`
+	Fields          issuetrackers.HTTPFields `yaml:"fields" mapstructure:"fields"`
+	Categories      []HTTPCategoryRule       `yaml:"categories" mapstructure:"categories"`
+	DefaultCategory string                   `yaml:"defaultCategory" mapstructure:"defaultCategory"`
+	BrowseURL       string                   `yaml:"browseURL" mapstructure:"browseURL"`
+	Timeout         int                      `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// HTTPCategoryRule associates the issues of the type and status to the category.
+type HTTPCategoryRule struct {
+	Type     string `yaml:"type" mapstructure:"type"`
+	Status   string `yaml:"status" mapstructure:"status"`
+	Category string `yaml:"category" mapstructure:"category"`
+}
+
+func GetHTTPTrackers() ([]HTTPTracker, error) {
+	var trackers []HTTPTracker
+	if err := viper.UnmarshalKey(HTTPTrackers, &trackers); err != nil {
+		return nil, fmt.Errorf("error unmarshaling http trackers: %w", err)
+	}
+
+	return trackers, nil
+}
+
+func newHTTPTracker(t HTTPTracker) (issuetrackers.HTTP, error) {
+	opts := []issuetrackers.HTTPOpt{
+		issuetrackers.WithFields(t.Fields),
+		issuetrackers.WithBatchSize(t.BatchSize),
+		issuetrackers.WithHTTPBrowseURL(t.BrowseURL),
+		issuetrackers.WithTimeout(time.Duration(t.Timeout) * time.Second),
+	}
+	if t.BatchURL != "" {
+		opts = append(opts, issuetrackers.WithBatchURL(t.BatchURL, t.IssuesPath))
+	}
+	for name, value := range t.Headers {
+		opts = append(opts, issuetrackers.WithHeader(name, value))
+	}
+	for _, r := range t.Categories {
+		category, err := entities.ParseIssueCategory(r.Category)
+		if err != nil {
+			return issuetrackers.HTTP{}, err
+		}
+		opts = append(opts, issuetrackers.WithCategoryRule(r.Type, r.Status, category))
+	}
+	if t.DefaultCategory != "" {
+		category, err := entities.ParseIssueCategory(t.DefaultCategory)
+		if err != nil {
+			return issuetrackers.HTTP{}, err
+		}
+		opts = append(opts, issuetrackers.WithHTTPDefaultCategory(category))
+	}
+
+	return issuetrackers.NewHTTP(t.IssueURL, opts...)
+}
+
+// ConfigureHTTPTrackers returns the http issues trackers of httpTrackers, in
+// order, labeled with their name in upper case.
+func ConfigureHTTPTrackers() ([]namedTracker, error) {
+	configs, err := GetHTTPTrackers()
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []namedTracker
+	for _, c := range configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("http tracker name is required")
+		}
+		label := strings.ToUpper(c.Name)
+		if slices.ContainsFunc(trackers, func(t namedTracker) bool { return t.label == label }) {
+			return nil, fmt.Errorf("http tracker %q defined more than once", c.Name)
+		}
+
+		fmt.Printf("using http tracker %s\n", c.Name)
+		t, err := newHTTPTracker(c)
+		if err != nil {
+			return nil, fmt.Errorf("http tracker %q: %w", c.Name, err)
+		}
+		trackers = append(trackers, namedTracker{label: label, it: t})
+	}
+
+	return trackers, nil
+}
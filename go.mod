@@ -4,6 +4,7 @@ go 1.25
 
 require (
 	github.com/ctreminiom/go-atlassian v1.6.1
+	github.com/google/go-github/v57 v57.0.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
@@ -45,7 +46,7 @@ require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/magiconair/properties v1.8.7 // indirect
 	github.com/mattn/go-shellwords v1.0.12
-	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/mitchellh/mapstructure v1.5.0
 	github.com/pelletier/go-toml/v2 v2.0.8 // indirect
 	github.com/spf13/afero v1.9.5 // indirect
 	github.com/spf13/cast v1.5.1 // indirect
@@ -7,6 +7,9 @@ type ExtractedIssue struct {
 	IssueKey         string        `yaml:"issueKey,omitempty"`
 	IssueSummary     string        `yaml:"issueSummary,omitempty"`
 	IssueCategory    IssueCategory `yaml:"issueCategory"`
+	WithoutCommit    bool          `yaml:"withoutCommit,omitempty"`
+	NotInScope       bool          `yaml:"notInScope,omitempty"`
+	CategoryName     string        `yaml:"categoryName,omitempty"`
 	Issue            `yaml:"issueDetail,omitempty"`
 	ParsedRepoRecord `yaml:"repoDetail,omitempty"`
 }
@@ -14,3 +17,10 @@ type ExtractedIssue struct {
 func (i ExtractedIssue) String() string {
 	return fmt.Sprintf("%s issue %s", i.IssueTracker, i.Issue.String())
 }
+
+// EpicFeatures groups the features of the same epic. The features without epic
+// are grouped with a nil Epic.
+type EpicFeatures struct {
+	Epic     *Issue           `yaml:"epic,omitempty"`
+	Features []ExtractedIssue `yaml:"features"`
+}
@@ -28,29 +28,41 @@ func (ct *IssueCategory) UnmarshalYAML(unmarshal func(interface{}) error) error
 		return err
 	}
 
+	c, err := ParseIssueCategory(s)
+	if err != nil {
+		return err
+	}
+	*ct = c
+
+	return nil
+}
+
+// ParseIssueCategory returns the category matching the name, case insensitive.
+func ParseIssueCategory(s string) (IssueCategory, error) {
 	switch strings.ToLower(s) {
 	case "closed_feature":
-		*ct = CLOSED_FEATURE
+		return CLOSED_FEATURE, nil
 	case "fixed_bug":
-		*ct = FIXED_BUG
+		return FIXED_BUG, nil
 	case "sub_task":
-		*ct = SUB_TASK
+		return SUB_TASK, nil
 	case "other":
-		*ct = OTHER
+		return OTHER, nil
 	default:
-		return fmt.Errorf("invalid CategoryType %q", s)
+		return OTHER, fmt.Errorf("invalid CategoryType %q", s)
 	}
-
-	return nil
 }
 
 type Issue struct {
-	Category     IssueCategory `yaml:"extractedCategory"`
-	IssueKey     string        `yaml:"issueKey,omitempty"`
-	IssueSummary string        `yaml:"issueSummary,omitempty"`
-	IssueType    string        `yaml:"issueType,omitempty"`
-	IssueStatus  string        `yaml:"issueStatus,omitempty"`
-	WebURL       string        `yaml:"webURL,omitempty"`
+	Category     IssueCategory  `yaml:"extractedCategory"`
+	IssueKey     string         `yaml:"issueKey,omitempty"`
+	IssueSummary string         `yaml:"issueSummary,omitempty"`
+	IssueType    string         `yaml:"issueType,omitempty"`
+	IssueStatus  string         `yaml:"issueStatus,omitempty"`
+	WebURL       string         `yaml:"webURL,omitempty"`
+	Labels       []string       `yaml:"labels,omitempty"`
+	Fields       map[string]any `yaml:"fields,omitempty"`
+	Parent       *Issue         `yaml:"parent,omitempty"`
 }
 
 func (i Issue) String() string {
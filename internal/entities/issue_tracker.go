@@ -1,8 +1,29 @@
 package entities
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrIssuesNotSupported is returned by the issues trackers not able to retrieve
+// the issues details. The issues are then built from the parsed repo records.
+var ErrIssuesNotSupported = errors.New("issues retrieval not supported")
 
 type IssuesTracker interface {
 	GetIssues(ctx context.Context, repo *EnrichedRepo, ids []string) ([]Issue, error)
 	GetKnownIssues(ctx context.Context, repo *EnrichedRepo) ([]Issue, error)
 }
+
+// ScopeTracker is implemented by the issues trackers able to retrieve the issues
+// planned for the release of the repo, used to cross-check the release scope
+// with the issues referenced by the commits.
+type ScopeTracker interface {
+	GetScopeIssues(ctx context.Context, repo *EnrichedRepo) ([]Issue, error)
+}
+
+// BrowsableTracker is implemented by the issues trackers exposing the template
+// of the web URL of their issues, where {key} is replaced by the issue key,
+// used to recognise the links to the issues pasted in the commits.
+type BrowsableTracker interface {
+	IssueURLTemplate() string
+}
@@ -11,10 +11,14 @@ const (
 	UNKNOWN CommitCategory = iota
 	FEATURE
 	BUG_FIX
+	IMPROVEMENT
+	SECURITY
+	DEPRECATION
+	REVERT
 )
 
 func (i CommitCategory) String() string {
-	return []string{"UNKNOWN", "FEATURE", "BUG_FIX"}[i]
+	return []string{"UNKNOWN", "FEATURE", "BUG_FIX", "IMPROVEMENT", "SECURITY", "DEPRECATION", "REVERT"}[i]
 }
 
 func (s CommitCategory) MarshalYAML() (interface{}, error) {
@@ -27,18 +31,35 @@ func (cct *CommitCategory) UnmarshalYAML(unmarshal func(interface{}) error) erro
 		return err
 	}
 
+	c, err := ParseCommitCategory(s)
+	if err != nil {
+		return err
+	}
+	*cct = c
+
+	return nil
+}
+
+// ParseCommitCategory returns the commit category matching the name, case insensitive.
+func ParseCommitCategory(s string) (CommitCategory, error) {
 	switch strings.ToLower(s) {
 	case "unknown":
-		*cct = UNKNOWN
+		return UNKNOWN, nil
 	case "feature":
-		*cct = FEATURE
+		return FEATURE, nil
 	case "bug_fix":
-		*cct = BUG_FIX
+		return BUG_FIX, nil
+	case "improvement":
+		return IMPROVEMENT, nil
+	case "security":
+		return SECURITY, nil
+	case "deprecation":
+		return DEPRECATION, nil
+	case "revert":
+		return REVERT, nil
 	default:
-		return fmt.Errorf("invalid CCType %q", s)
+		return UNKNOWN, fmt.Errorf("invalid CCType %q", s)
 	}
-
-	return nil
 }
 
 type ParsedRepoRecord struct {
@@ -46,10 +67,39 @@ type ParsedRepoRecord struct {
 	ParsedSummary      string         `yaml:"parsedSummary,omitempty"`
 	ParsedCategory     CommitCategory `yaml:"parsedCategory,omitempty"`
 	ParsedKey          string         `yaml:"parsedKey,omitempty"`
+	ParsedProject      string         `yaml:"parsedProject,omitempty"`
 	ParsedIssueTracker string         `yaml:"parsedIssueTracker"`
 	Parser             string         `yaml:"parser,omitempty"`
 	ParsedType         string         `yaml:"parsedType,omitempty"`
 	IsBreakingChange   bool           `yaml:"isBreakingChange,omitempty"`
+	MergeRequestID     string         `yaml:"mergeRequestId,omitempty"`
+	MergeRequestURL    string         `yaml:"mergeRequestURL,omitempty"`
+	// BreakingChange is the description of the breaking change given in the
+	// BREAKING CHANGE footer of the message.
+	BreakingChange string `yaml:"breakingChange,omitempty"`
+	// Trailers holds the values of the trailers of the message by their
+	// lowercase token, e.g. refs, co-authored-by or release-note.
+	Trailers map[string][]string `yaml:"trailers,omitempty"`
+}
+
+// IssueRef returns the key of the issue of the project, as project#key, or the
+// key itself for the issues of the project of the repo.
+func IssueRef(project, key string) string {
+	if project == "" {
+		return key
+	}
+
+	return project + "#" + key
+}
+
+// SplitIssueRef returns the project and the key of the issue reference built by IssueRef.
+func SplitIssueRef(ref string) (string, string) {
+	i := strings.LastIndex(ref, "#")
+	if i < 0 {
+		return "", ref
+	}
+
+	return ref[:i], ref[i+1:]
 }
 
 func (c ParsedRepoRecord) String() string {
@@ -3,17 +3,48 @@ package entities
 import "fmt"
 
 type Repo struct {
-	Label            string         `yaml:"label,omitempty"`
-	ServiceName      string         `yaml:"serviceName,omitempty"`
-	ID               string         `yaml:"gitRepoID,omitempty"`
-	FromTag          string         `yaml:"previousVersion,omitempty"`
-	ToTag            string         `yaml:"version,omitempty"`
-	CheckTag         string         `yaml:"checkVersion,omitempty"`
-	Project          string         `yaml:"jiraProject,omitempty"`
-	Component        string         `yaml:"jiraComponent,omitempty"`
-	GitRepoURL       string         `yaml:"gitRepoURL,omitempty"`
-	GitReleaseURL    string         `yaml:"gitReleaseURL,omitempty"`
-	CustomAttributes map[string]any `yaml:"customAttributes,omitempty"`
+	Label             string `yaml:"label,omitempty"`
+	ServiceName       string `yaml:"serviceName,omitempty"`
+	ID                string `yaml:"gitRepoID,omitempty"`
+	GitProfile        string `yaml:"gitProfile,omitempty"`
+	FromTag           string `yaml:"previousVersion,omitempty"`
+	ToTag             string `yaml:"version,omitempty"`
+	CheckTag          string `yaml:"checkVersion,omitempty"`
+	MRTargetBranch    string `yaml:"mrTargetBranch,omitempty"`
+	Project           string `yaml:"jiraProject,omitempty"`
+	Component         string `yaml:"jiraComponent,omitempty"`
+	FixVersion        string `yaml:"jiraFixVersion,omitempty"`
+	KnownIssuesJQL    string `yaml:"jiraKnownIssuesJQL,omitempty"`
+	KnownIssuesFilter `yaml:",inline"`
+	GitRepoURL        string         `yaml:"gitRepoURL,omitempty"`
+	GitReleaseURL     string         `yaml:"gitReleaseURL,omitempty"`
+	CustomAttributes  map[string]any `yaml:"customAttributes,omitempty"`
+}
+
+// KnownIssuesFilter selects the open issues of the git repository reported as known issues.
+type KnownIssuesFilter struct {
+	Labels    []string `yaml:"gitKnownIssuesLabels,omitempty"`
+	Milestone string   `yaml:"gitKnownIssuesMilestone,omitempty"`
+	IssueType string   `yaml:"gitKnownIssuesType,omitempty"`
+}
+
+func (f KnownIssuesFilter) IsEmpty() bool {
+	return len(f.Labels) == 0 && f.Milestone == "" && f.IssueType == ""
+}
+
+// WithDefaults returns the filter with the criteria not set taken from d.
+func (f KnownIssuesFilter) WithDefaults(d KnownIssuesFilter) KnownIssuesFilter {
+	if len(f.Labels) == 0 {
+		f.Labels = d.Labels
+	}
+	if f.Milestone == "" {
+		f.Milestone = d.Milestone
+	}
+	if f.IssueType == "" {
+		f.IssueType = d.IssueType
+	}
+
+	return f
 }
 
 type EnrichedRepo struct {
@@ -13,6 +13,11 @@ type RepoRecord struct {
 	CreatedAt *time.Time `yaml:"createdAt,omitempty"`
 	WebURL    string     `yaml:"webURL,omitempty"`
 	Origin    string     `yaml:"origin,omitempty"`
+	// CommitIDs holds, for a merge request, the ids of the commits it merged.
+	CommitIDs []string `yaml:"-"`
+	// MergeCommitIDs holds, for a merge request, the ids of its merge and
+	// squash commits, whose revert reverts the whole merge request.
+	MergeCommitIDs []string `yaml:"-"`
 }
 
 func (r RepoRecord) String() string {
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/happyagosmith/jig/internal/entities"
+)
+
+// Bump is the part of the semantic version bumped by the issues of a category.
+type Bump string
+
+const (
+	BumpMajor Bump = "major"
+	BumpMinor Bump = "minor"
+	BumpPatch Bump = "patch"
+)
+
+// Category is a section of the generated values, listing for each repo the
+// issues matching any of its rules, in addition to the features and the bugs.
+// The issues are added to the first matching category only, and no longer to
+// the features or the bugs.
+type Category struct {
+	Name string
+	// IssueTypes are the filters type:status, or type for any status, matching
+	// the type and the status of the issues, e.g. Improvement:Done.
+	IssueTypes []string
+	// Labels match the labels of the GitLab and GitHub issues. A label ending
+	// with * matches the labels with the prefix, e.g. type::*.
+	Labels []string
+	// CommitTypes match the type of the commits, e.g. perf or refactor.
+	CommitTypes []string
+	// CommitCategories match the category mapped from the type of the commits
+	// reported with the commit details only, e.g. IMPROVEMENT.
+	CommitCategories []entities.CommitCategory
+	Bump             Bump
+	// omitEmpty leaves out of the generated values the category without issues.
+	omitEmpty bool
+}
+
+// defaultCategories list the commits of the types mapped to the IMPROVEMENT,
+// SECURITY and DEPRECATION categories, after the configured categories. A
+// configured category with the same name replaces the default one.
+var defaultCategories = []Category{
+	{Name: "improvements", CommitCategories: []entities.CommitCategory{entities.IMPROVEMENT}, Bump: BumpPatch, omitEmpty: true},
+	{Name: "security", CommitCategories: []entities.CommitCategory{entities.SECURITY}, Bump: BumpPatch, omitEmpty: true},
+	{Name: "deprecations", CommitCategories: []entities.CommitCategory{entities.DEPRECATION}, Bump: BumpMinor, omitEmpty: true},
+}
+
+// reservedValues are the keys of the generated values that the categories can't use.
+var reservedValues = []string{"features", "bugs", "knownIssues", "breakingChange", "reverts",
+	"scopeDiscrepancies", "featuresByEpic", "gitRepos"}
+
+func (c Category) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("category without name")
+	}
+	for _, r := range reservedValues {
+		if strings.EqualFold(c.Name, r) {
+			return fmt.Errorf("invalid category %q: %s is a generated value", c.Name, r)
+		}
+	}
+	switch c.Bump {
+	case "", BumpMajor, BumpMinor, BumpPatch:
+	default:
+		return fmt.Errorf("invalid bump %q of the category %q, expected major, minor or patch", c.Bump, c.Name)
+	}
+
+	return nil
+}
+
+func (c Category) matches(ei entities.ExtractedIssue) bool {
+	for _, f := range c.IssueTypes {
+		if ei.Issue.IssueType == "" {
+			break
+		}
+		t, s, _ := strings.Cut(f, ":")
+		if strings.EqualFold(t, ei.Issue.IssueType) && (s == "" || strings.EqualFold(s, ei.Issue.IssueStatus)) {
+			return true
+		}
+	}
+	for _, l := range c.Labels {
+		for _, label := range ei.Issue.Labels {
+			if matchLabel(l, label) {
+				return true
+			}
+		}
+	}
+	for _, t := range c.CommitTypes {
+		if ei.ParsedRepoRecord.ParsedType != "" && strings.EqualFold(t, ei.ParsedRepoRecord.ParsedType) {
+			return true
+		}
+	}
+	if ei.Issue.IssueKey == "" && slices.Contains(c.CommitCategories, ei.ParsedRepoRecord.ParsedCategory) {
+		return true
+	}
+
+	return false
+}
+
+func matchLabel(pattern, label string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return len(label) >= len(prefix) && strings.EqualFold(label[:len(prefix)], prefix)
+	}
+
+	return strings.EqualFold(pattern, label)
+}
+
+// addToCategory adds the issue to the first category it matches, returning
+// false if it matches none. The flags report the part of the version to bump.
+func (m *Model) addToCategory(label string, ei entities.ExtractedIssue) (bool, bool, bool, bool) {
+	for _, c := range m.categories {
+		if !c.matches(ei) {
+			continue
+		}
+		ei.CategoryName = c.Name
+		if m.GValues.Categories[c.Name] == nil {
+			m.GValues.Categories[c.Name] = map[string][]entities.ExtractedIssue{}
+		}
+		m.GValues.Categories[c.Name][label] = append(m.GValues.Categories[c.Name][label], ei)
+		fmt.Printf("added to the category %s\n", c.Name)
+		switch c.Bump {
+		case BumpMajor:
+			return true, true, false, false
+		case BumpMinor:
+			return true, false, true, false
+		default:
+			return true, false, false, true
+		}
+	}
+
+	return false, false, false, false
+}
+
+// addDefaultCategories appends the default categories not replaced by a
+// configured category with the same name.
+func (m *Model) addDefaultCategories() {
+	for _, d := range defaultCategories {
+		if !slices.ContainsFunc(m.categories, func(c Category) bool { return strings.EqualFold(c.Name, d.Name) }) {
+			m.categories = append(m.categories, d)
+		}
+	}
+}
+
+func (m *Model) resetCategories() {
+	m.GValues.Categories = map[string]map[string][]entities.ExtractedIssue{}
+	for _, c := range m.categories {
+		if !c.omitEmpty {
+			m.GValues.Categories[c.Name] = map[string][]entities.ExtractedIssue{}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -18,7 +19,20 @@ type GeneratedValues struct {
 	Bugs           map[string][]entities.ExtractedIssue `yaml:"bugs"`
 	KnownIssues    map[string][]entities.ExtractedIssue `yaml:"knownIssues"`
 	BreakingChange map[string][]entities.ExtractedIssue `yaml:"breakingChange"`
-	GitRepos       []entities.EnrichedRepo              `yaml:"gitRepos"`
+	// Reverts lists, for each repo, the issues of the commits of earlier
+	// releases reverted by the release.
+	Reverts map[string][]entities.ExtractedIssue `yaml:"reverts,omitempty"`
+	// ScopeDiscrepancies lists, for each repo, the issues of the release scope
+	// without commits and the issues referenced by commits not in the release scope.
+	ScopeDiscrepancies map[string][]entities.ExtractedIssue `yaml:"scopeDiscrepancies,omitempty"`
+	// FeaturesByEpic groups, for each repo, the features by epic when the
+	// issues tracker provides the epics.
+	FeaturesByEpic map[string][]entities.EpicFeatures `yaml:"featuresByEpic,omitempty"`
+	GitRepos       []entities.EnrichedRepo            `yaml:"gitRepos"`
+	// Categories lists, for each category and repo, the issues of the
+	// category. Each category is a generated value named after it, like the
+	// default improvements, security and deprecations.
+	Categories map[string]map[string][]entities.ExtractedIssue `yaml:",inline"`
 }
 
 type Model struct {
@@ -28,8 +42,10 @@ type Model struct {
 		label string
 		it    entities.IssuesTracker
 	}
-	repoService entities.RepoService
-	y           *yamlfile.Yaml
+	categories   []Category
+	repoService  entities.RepoService
+	repoServices map[string]entities.RepoService
+	y            *yamlfile.Yaml
 }
 
 type ModelOpt func(*Model)
@@ -42,6 +58,20 @@ func WithRepoService(repoService entities.RepoService) ModelOpt {
 	}
 }
 
+// WithGitProfile sets the repo service used for the services referencing the
+// git profile name with the gitProfile field.
+func WithGitProfile(name string, repoService entities.RepoService) ModelOpt {
+	return func(m *Model) {
+		if name == "" || repoService == nil {
+			return
+		}
+		if m.repoServices == nil {
+			m.repoServices = map[string]entities.RepoService{}
+		}
+		m.repoServices[name] = repoService
+	}
+}
+
 func WithIssueTracker(label string, it entities.IssuesTracker) ModelOpt {
 	return func(m *Model) {
 		if label != "" {
@@ -53,6 +83,13 @@ func WithIssueTracker(label string, it entities.IssuesTracker) ModelOpt {
 	}
 }
 
+// WithCategory adds the category of issues, matched in order of addition.
+func WithCategory(c Category) ModelOpt {
+	return func(m *Model) {
+		m.categories = append(m.categories, c)
+	}
+}
+
 func New(values []byte, opts ...ModelOpt) (*Model, error) {
 	var m Model
 	err := yaml.Unmarshal(values, &m)
@@ -75,6 +112,12 @@ func New(values []byte, opts ...ModelOpt) (*Model, error) {
 	for _, o := range opts {
 		o(&m)
 	}
+	for _, c := range m.categories {
+		if err := c.validate(); err != nil {
+			return nil, err
+		}
+	}
+	m.addDefaultCategories()
 
 	return &m, nil
 }
@@ -123,18 +166,35 @@ func (m *Model) UpdateWithReposVersions(rootPath string) error {
 	return nil
 }
 
-func (m *Model) UpdateWithReposInfos() error {
-	if m.repoService == nil {
-		return fmt.Errorf("vcs not set")
+func (m *Model) getRepoService(repo entities.Repo) (entities.RepoService, error) {
+	if repo.GitProfile == "" {
+		if m.repoService == nil {
+			return nil, fmt.Errorf("vcs not set")
+		}
+		return m.repoService, nil
+	}
+
+	rs, ok := m.repoServices[repo.GitProfile]
+	if !ok {
+		return nil, fmt.Errorf("git profile %q of the repo %s not configured", repo.GitProfile, repo.Label)
 	}
+
+	return rs, nil
+}
+
+func (m *Model) UpdateWithReposInfos() error {
 	for i, repo := range m.GitRepos {
+		repoService, err := m.getRepoService(repo)
+		if err != nil {
+			return err
+		}
 
-		rUrl, err := m.repoService.GetRepoURL(repo.ID)
+		rUrl, err := repoService.GetRepoURL(repo.ID)
 		if err != nil {
 			return err
 		}
 
-		vUrl, err := m.repoService.GetReleaseURL(repo.ID, repo.ToTag)
+		vUrl, err := repoService.GetReleaseURL(repo.ID, repo.ToTag)
 		if err != nil {
 			return err
 		}
@@ -159,6 +219,8 @@ func (m *Model) EnrichWithRepos() error {
 	m.GValues.Bugs = map[string][]entities.ExtractedIssue{}
 	m.GValues.KnownIssues = map[string][]entities.ExtractedIssue{}
 	m.GValues.BreakingChange = map[string][]entities.ExtractedIssue{}
+	m.GValues.Reverts = map[string][]entities.ExtractedIssue{}
+	m.resetCategories()
 
 	m.GValues.GitRepos = []entities.EnrichedRepo{}
 	for _, repo := range m.GitRepos {
@@ -175,9 +237,14 @@ func (m *Model) EnrichWithRepos() error {
 
 		fmt.Printf("\nprocessing %s", repo.String())
 
-		pRecords, err := m.repoService.GetParsedRecords(repo.ID, fc, tc, "")
+		repoService, err := m.getRepoService(repo)
+		if err != nil {
+			return err
+		}
+
+		pRecords, err := repoService.GetParsedRecords(repo.ID, fc, tc, repo.MRTargetBranch)
 		if err != nil {
-			return err	
+			return err
 		}
 
 		enrichedRepo.ParsedCommits = pRecords
@@ -195,6 +262,10 @@ func (m *Model) EnrichWithIssueTrackers() error {
 	m.GValues.Bugs = map[string][]entities.ExtractedIssue{}
 	m.GValues.KnownIssues = map[string][]entities.ExtractedIssue{}
 	m.GValues.BreakingChange = map[string][]entities.ExtractedIssue{}
+	m.GValues.Reverts = map[string][]entities.ExtractedIssue{}
+	m.resetCategories()
+	m.GValues.ScopeDiscrepancies = map[string][]entities.ExtractedIssue{}
+	m.GValues.FeaturesByEpic = map[string][]entities.EpicFeatures{}
 
 	for i := range m.GValues.GitRepos {
 		repo := &m.GValues.GitRepos[i]
@@ -202,6 +273,9 @@ func (m *Model) EnrichWithIssueTrackers() error {
 		if err != nil {
 			return err
 		}
+		if groups := groupByEpic(m.GValues.Features[repo.Label]); groups != nil {
+			m.GValues.FeaturesByEpic[repo.Label] = groups
+		}
 
 		sv, _ := computeSemanticVersion(repo.FromTag, repo.HasBreaking, repo.HasNewFeature, repo.HasBugFixed)
 		fmt.Printf("\ncurrent version for the repo \"%s\" is: %s, suggested version \"%s\"\n", repo.Label, repo.FromTag, sv)
@@ -237,24 +311,50 @@ func (m *Model) enrichRepoWithIssueTracker(repo *entities.EnrichedRepo) error {
 			continue
 		}
 
+		scope, err := m.getScopeIssues(repo, issuesTracker.label, issuesTracker.it)
+		if err != nil {
+			return err
+		}
+		isFound := map[string]bool{}
+
 		if len(keys) != 0 {
 			fmt.Printf("retrieving issues info from the issues tracker \"%s\" for the repo \"%s\"\n", issuesTracker.label, repo.Label)
 			issues, err := issuesTracker.it.GetIssues(context.Background(), repo, keys)
-			if err != nil {
+			var hasBreaking, hasNewFeature, hasBugFixed bool
+			switch {
+			case errors.Is(err, entities.ErrIssuesNotSupported):
+				fmt.Printf("adding issues with only commit details \"%s\"\n", issuesTracker.label)
+				hasBreaking, hasNewFeature, hasBugFixed = m.addParsedCommitAsIssues(repo.Label, commits)
+			case err != nil:
 				return err
+			default:
+				extractedIssues := make([]entities.ExtractedIssue, 0, len(issues))
+				for _, issue := range issues {
+					ei := entities.ExtractedIssue{
+						IssueTracker:     issuesTracker.label,
+						IssueKey:         issue.IssueKey,
+						IssueSummary:     issue.IssueSummary,
+						IssueCategory:    issue.Category,
+						Issue:            issue,
+						ParsedRepoRecord: commits[issue.IssueKey],
+					}
+					isFound[issue.IssueKey] = true
+					if scope != nil && !scope.contains(issue.IssueKey) {
+						fmt.Printf("issue %s referenced by the commits is not in the release scope\n", issue.IssueKey)
+						ei.NotInScope = true
+						m.GValues.ScopeDiscrepancies[repo.Label] = append(m.GValues.ScopeDiscrepancies[repo.Label], ei)
+					}
+					extractedIssues = append(extractedIssues, ei)
+				}
+				hasBreaking, hasNewFeature, hasBugFixed = m.addFoundIssues(repo.Label, extractedIssues)
 			}
-			extractedIssues := make([]entities.ExtractedIssue, 0, len(issues))
-			for _, issue := range issues {
-				extractedIssues = append(extractedIssues, entities.ExtractedIssue{
-					IssueTracker:     issuesTracker.label,
-					IssueKey:         issue.IssueKey,
-					IssueSummary:     issue.IssueSummary,
-					IssueCategory:    issue.Category,
-					Issue:            issue,
-					ParsedRepoRecord: commits[issue.IssueKey],
-				})
-			}
-			hasBreaking, hasNewFeature, hasBugFixed := m.addFoundIssues(repo.Label, extractedIssues)
+			repo.HasBreaking = repo.HasBreaking || hasBreaking
+			repo.HasNewFeature = repo.HasNewFeature || hasNewFeature
+			repo.HasBugFixed = repo.HasBugFixed || hasBugFixed
+		}
+
+		if scope != nil {
+			hasBreaking, hasNewFeature, hasBugFixed := m.addScopeIssuesWithoutCommit(repo.Label, issuesTracker.label, scope, isFound)
 			repo.HasBreaking = repo.HasBreaking || hasBreaking
 			repo.HasNewFeature = repo.HasNewFeature || hasNewFeature
 			repo.HasBugFixed = repo.HasBugFixed || hasBugFixed
@@ -276,6 +376,95 @@ func (m *Model) enrichRepoWithIssueTracker(repo *entities.EnrichedRepo) error {
 	return nil
 }
 
+// releaseScope holds the issues planned for the release of a repo.
+type releaseScope struct {
+	issues []entities.Issue
+	keys   map[string]bool
+}
+
+func (rs *releaseScope) contains(key string) bool {
+	return rs.keys[key]
+}
+
+// getScopeIssues returns the release scope of the repo when the issues tracker
+// supports it and the scope is configured, nil otherwise.
+func (m *Model) getScopeIssues(repo *entities.EnrichedRepo, label string, it entities.IssuesTracker) (*releaseScope, error) {
+	st, ok := it.(entities.ScopeTracker)
+	if !ok {
+		return nil, nil
+	}
+
+	issues, err := st.GetScopeIssues(context.Background(), repo)
+	if err != nil {
+		return nil, err
+	}
+	if issues == nil {
+		return nil, nil
+	}
+	fmt.Printf("retrieved %d issues of the release scope from the issues tracker \"%s\" for the repo \"%s\"\n", len(issues), label, repo.Label)
+
+	scope := &releaseScope{issues: issues, keys: map[string]bool{}}
+	for _, issue := range issues {
+		scope.keys[issue.IssueKey] = true
+	}
+
+	return scope, nil
+}
+
+// addScopeIssuesWithoutCommit adds the issues of the release scope not found
+// through the commits, flagging them as discrepancies.
+func (m *Model) addScopeIssuesWithoutCommit(label, it string, scope *releaseScope, isFound map[string]bool) (bool, bool, bool) {
+	extractedIssues := []entities.ExtractedIssue{}
+	for _, issue := range scope.issues {
+		if isFound[issue.IssueKey] {
+			continue
+		}
+		fmt.Printf("issue %s of the release scope has no commit\n", issue.IssueKey)
+		ei := entities.ExtractedIssue{
+			IssueTracker:  it,
+			IssueKey:      issue.IssueKey,
+			IssueSummary:  issue.IssueSummary,
+			IssueCategory: issue.Category,
+			WithoutCommit: true,
+			Issue:         issue,
+		}
+		m.GValues.ScopeDiscrepancies[label] = append(m.GValues.ScopeDiscrepancies[label], ei)
+		extractedIssues = append(extractedIssues, ei)
+	}
+
+	return m.addFoundIssues(label, extractedIssues)
+}
+
+// groupByEpic groups the features by epic, in order of appearance, with the
+// features without epic in the last group. It returns nil if no feature has an epic.
+func groupByEpic(features []entities.ExtractedIssue) []entities.EpicFeatures {
+	var groups []entities.EpicFeatures
+	var withoutEpic []entities.ExtractedIssue
+	index := map[string]int{}
+	for _, f := range features {
+		epic := f.Issue.Parent
+		if epic == nil {
+			withoutEpic = append(withoutEpic, f)
+			continue
+		}
+		i, ok := index[epic.IssueKey]
+		if !ok {
+			i = len(groups)
+			index[epic.IssueKey] = i
+			groups = append(groups, entities.EpicFeatures{Epic: epic})
+		}
+		groups[i].Features = append(groups[i].Features, f)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	if len(withoutEpic) > 0 {
+		groups = append(groups, entities.EpicFeatures{Features: withoutEpic})
+	}
+
+	return groups
+}
+
 func (m *Model) addFoundIssues(label string, issues []entities.ExtractedIssue) (bool, bool, bool) {
 	var hasBreaking, hasNewFeature, hasBugFixed bool
 
@@ -285,11 +474,21 @@ func (m *Model) addFoundIssues(label string, issues []entities.ExtractedIssue) (
 			fmt.Print("subTask not added\n")
 			continue
 		}
+		if issue.ParsedRepoRecord.ParsedCategory == entities.REVERT {
+			m.GValues.Reverts[label] = append(m.GValues.Reverts[label], issue)
+			fmt.Print("added as revert\n")
+			hasBugFixed = true
+			continue
+		}
 		if issue.ParsedRepoRecord.IsBreakingChange {
 			m.GValues.BreakingChange[label] = append(m.GValues.BreakingChange[label], issue)
 			fmt.Print("added as Breaking Change\n")
 			hasBreaking = true
 		}
+		if ok, major, minor, patch := m.addToCategory(label, issue); ok {
+			hasBreaking, hasNewFeature, hasBugFixed = hasBreaking || major, hasNewFeature || minor, hasBugFixed || patch
+			continue
+		}
 		if issue.Issue.Category == entities.CLOSED_FEATURE {
 			m.GValues.Features[label] = append(m.GValues.Features[label], issue)
 			fmt.Print("added as feature\n")
@@ -320,6 +519,22 @@ func (m *Model) addParsedCommitAsIssues(label string, commits map[string]entitie
 			IssueKey:         c.ParsedKey,
 			IssueSummary:     summary,
 			ParsedRepoRecord: c}
+		if c.ParsedCategory == entities.REVERT {
+			m.GValues.Reverts[label] = append(m.GValues.Reverts[label], ei)
+			fmt.Print("added as revert\n")
+			hasBugFixed = true
+			continue
+		}
+		if c.IsBreakingChange {
+			m.GValues.BreakingChange[label] = append(m.GValues.BreakingChange[label], ei)
+			fmt.Print("added as Breaking Change\n")
+			hasBreaking = true
+		}
+		ei.IssueCategory = entities.OTHER
+		if ok, major, minor, patch := m.addToCategory(label, ei); ok {
+			hasBreaking, hasNewFeature, hasBugFixed = hasBreaking || major, hasNewFeature || minor, hasBugFixed || patch
+			continue
+		}
 		if c.ParsedCategory == entities.FEATURE {
 			ei.IssueCategory = entities.CLOSED_FEATURE
 			m.GValues.Features[label] = append(m.GValues.Features[label], ei)
@@ -332,12 +547,6 @@ func (m *Model) addParsedCommitAsIssues(label string, commits map[string]entitie
 			fmt.Print("added as bug\n")
 			hasBugFixed = true
 		}
-		if c.IsBreakingChange {
-			m.GValues.BreakingChange[label] = append(m.GValues.BreakingChange[label], ei)
-			fmt.Print("added as Breaking Change\n")
-			hasBreaking = true
-		}
-
 	}
 
 	return hasBreaking, hasNewFeature, hasBugFixed
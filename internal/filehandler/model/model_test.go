@@ -158,6 +158,82 @@ services:
 	}
 }
 
+func TestEnrichWithGitProfile(t *testing.T) {
+	defaultRepoParser := new(MockRepoParser)
+	defaultRepoParser.On("GetParsedRecords", "repo1", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{}, nil)
+
+	profileRepoParser := new(MockRepoParser)
+	profileRepoParser.On("GetParsedRecords", "owner/repo2", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{}, nil)
+
+	values := []byte(`
+services:
+  - label: label1
+    gitRepoID: repo1
+    previousVersion: 0.0.0
+    version: 1.0.0
+  - label: label2
+    gitRepoID: owner/repo2
+    gitProfile: github
+    previousVersion: 0.0.0
+    version: 1.0.0
+`)
+
+	m, err := model.New(values,
+		model.WithRepoService(defaultRepoParser),
+		model.WithGitProfile("github", profileRepoParser))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	defaultRepoParser.AssertExpectations(t)
+	profileRepoParser.AssertExpectations(t)
+}
+
+func TestEnrichWithMRTargetBranch(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repo1", "0.0.0", "1.0.0", "develop").Return([]entities.ParsedRepoRecord{}, nil)
+	mockRepoParser.On("GetParsedRecords", "repo2", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{}, nil)
+
+	values := []byte(`
+services:
+  - label: label1
+    gitRepoID: repo1
+    previousVersion: 0.0.0
+    version: 1.0.0
+    mrTargetBranch: develop
+  - label: label2
+    gitRepoID: repo2
+    previousVersion: 0.0.0
+    version: 1.0.0
+`)
+
+	m, err := model.New(values, model.WithRepoService(mockRepoParser))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	mockRepoParser.AssertExpectations(t)
+}
+
+func TestEnrichWithUnknownGitProfile(t *testing.T) {
+	values := []byte(`
+services:
+  - label: label1
+    gitRepoID: repo1
+    gitProfile: unknown
+    previousVersion: 0.0.0
+    version: 1.0.0
+`)
+
+	m, err := model.New(values, model.WithRepoService(new(MockRepoParser)))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.Error(t, err)
+}
+
 type MockIssueTracker struct {
 	mock.Mock
 }
@@ -492,3 +568,317 @@ func TestEnrichWithIssueTrackers(t *testing.T) {
 		})
 	}
 }
+
+func TestEnrichWithIssueTrackerNotSupported(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repoID", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{
+		{ParsedKey: "1", ParsedIssueTracker: "GIT", ParsedCategory: entities.FEATURE, ParsedSummary: "summary"},
+	}, nil)
+
+	mockIssueTracker := new(MockIssueTracker)
+	mockIssueTracker.On("GetIssues", []string{"1"}).Return([]entities.Issue{}, entities.ErrIssuesNotSupported)
+	mockIssueTracker.On("GetKnownIssues", mock.Anything).Return([]entities.Issue{}, nil)
+
+	values := []byte("" +
+		"services:\n" +
+		"  - label: label1\n" +
+		"    gitRepoID: repoID\n" +
+		"    previousVersion: 0.0.0\n" +
+		"    version: 1.0.0\n")
+
+	m, err := model.New(values,
+		model.WithRepoService(mockRepoParser),
+		model.WithIssueTracker("GIT", mockIssueTracker))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	err = m.EnrichWithIssueTrackers()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(m.GValues.Features["label1"]))
+	assert.Equal(t, "summary", m.GValues.Features["label1"][0].IssueSummary)
+	assert.True(t, m.GValues.GitRepos[0].HasNewFeature)
+	mockIssueTracker.AssertCalled(t, "GetKnownIssues", mock.Anything)
+}
+
+type MockScopeTracker struct {
+	MockIssueTracker
+}
+
+func (m *MockScopeTracker) GetScopeIssues(_ context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
+	args := m.Called(repo.FixVersion)
+	return args.Get(0).([]entities.Issue), args.Error(1)
+}
+
+func TestEnrichWithReleaseScope(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repoID", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{
+		{ParsedKey: "AAA-1", ParsedIssueTracker: "JIRA", ParsedCategory: entities.FEATURE},
+		{ParsedKey: "AAA-2", ParsedIssueTracker: "JIRA", ParsedCategory: entities.BUG_FIX},
+	}, nil)
+
+	mockScopeTracker := new(MockScopeTracker)
+	mockScopeTracker.On("GetIssues", []string{"AAA-1", "AAA-2"}).Return([]entities.Issue{
+		{IssueKey: "AAA-1", Category: entities.CLOSED_FEATURE},
+		{IssueKey: "AAA-2", Category: entities.FIXED_BUG},
+	}, nil)
+	mockScopeTracker.On("GetKnownIssues", mock.Anything).Return([]entities.Issue{}, nil)
+	mockScopeTracker.On("GetScopeIssues", "service-{version}").Return([]entities.Issue{
+		{IssueKey: "AAA-1", Category: entities.CLOSED_FEATURE},
+		{IssueKey: "AAA-3", Category: entities.FIXED_BUG},
+	}, nil)
+
+	values := []byte("" +
+		"services:\n" +
+		"  - label: label1\n" +
+		"    gitRepoID: repoID\n" +
+		"    previousVersion: 0.0.0\n" +
+		"    version: 1.0.0\n" +
+		"    jiraFixVersion: service-{version}\n")
+
+	m, err := model.New(values,
+		model.WithRepoService(mockRepoParser),
+		model.WithIssueTracker("JIRA", mockScopeTracker))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	err = m.EnrichWithIssueTrackers()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(m.GValues.Features["label1"]))
+	assert.False(t, m.GValues.Features["label1"][0].NotInScope)
+	assert.Equal(t, 2, len(m.GValues.Bugs["label1"]))
+	assert.Equal(t, "AAA-2", m.GValues.Bugs["label1"][0].IssueKey)
+	assert.True(t, m.GValues.Bugs["label1"][0].NotInScope)
+	assert.Equal(t, "AAA-3", m.GValues.Bugs["label1"][1].IssueKey)
+	assert.True(t, m.GValues.Bugs["label1"][1].WithoutCommit)
+
+	discrepancies := m.GValues.ScopeDiscrepancies["label1"]
+	assert.Equal(t, 2, len(discrepancies))
+	assert.Equal(t, "AAA-2", discrepancies[0].IssueKey)
+	assert.Equal(t, "AAA-3", discrepancies[1].IssueKey)
+}
+
+func TestEnrichWithFeaturesByEpic(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repoID", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{
+		{ParsedKey: "AAA-1", ParsedIssueTracker: "JIRA"},
+		{ParsedKey: "AAA-2", ParsedIssueTracker: "JIRA"},
+		{ParsedKey: "AAA-3", ParsedIssueTracker: "JIRA"},
+	}, nil)
+
+	epic := &entities.Issue{IssueKey: "EPIC-1", IssueSummary: "epic"}
+	mockIssueTracker := new(MockIssueTracker)
+	mockIssueTracker.On("GetIssues", []string{"AAA-1", "AAA-2", "AAA-3"}).Return([]entities.Issue{
+		{IssueKey: "AAA-1", Category: entities.CLOSED_FEATURE, Parent: epic},
+		{IssueKey: "AAA-2", Category: entities.CLOSED_FEATURE},
+		{IssueKey: "AAA-3", Category: entities.CLOSED_FEATURE, Parent: epic},
+	}, nil)
+	mockIssueTracker.On("GetKnownIssues", mock.Anything).Return([]entities.Issue{}, nil)
+
+	values := []byte("" +
+		"services:\n" +
+		"  - label: label1\n" +
+		"    gitRepoID: repoID\n" +
+		"    previousVersion: 0.0.0\n" +
+		"    version: 1.0.0\n")
+
+	m, err := model.New(values,
+		model.WithRepoService(mockRepoParser),
+		model.WithIssueTracker("JIRA", mockIssueTracker))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	err = m.EnrichWithIssueTrackers()
+	assert.NoError(t, err)
+
+	groups := m.GValues.FeaturesByEpic["label1"]
+	assert.Equal(t, 2, len(groups))
+	assert.Equal(t, "EPIC-1", groups[0].Epic.IssueKey)
+	assert.Equal(t, 2, len(groups[0].Features))
+	assert.Equal(t, "AAA-1", groups[0].Features[0].IssueKey)
+	assert.Equal(t, "AAA-3", groups[0].Features[1].IssueKey)
+	assert.Nil(t, groups[1].Epic)
+	assert.Equal(t, "AAA-2", groups[1].Features[0].IssueKey)
+}
+
+func TestEnrichWithCommitCategories(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repoID", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{
+		{ParsedKey: "SILK-1", ParsedIssueTracker: "SILK", ParsedCategory: entities.IMPROVEMENT, ParsedSummary: "faster"},
+		{ParsedKey: "SILK-2", ParsedIssueTracker: "SILK", ParsedCategory: entities.SECURITY, ParsedSummary: "safer"},
+		{ParsedKey: "SILK-3", ParsedIssueTracker: "SILK", ParsedCategory: entities.DEPRECATION, ParsedSummary: "older"},
+		{ParsedKey: "SILK-4", ParsedIssueTracker: "SILK", ParsedCategory: entities.UNKNOWN, ParsedSummary: "ignored"},
+	}, nil)
+
+	values := []byte("" +
+		"services:\n" +
+		"  - label: label1\n" +
+		"    gitRepoID: repoID\n" +
+		"    previousVersion: 0.0.0\n" +
+		"    version: 1.0.0\n")
+
+	m, err := model.New(values,
+		model.WithRepoService(mockRepoParser),
+		model.WithIssueTracker("SILK", nil))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	err = m.EnrichWithIssueTrackers()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(m.GValues.Categories["improvements"]["label1"]))
+	assert.Equal(t, "faster", m.GValues.Categories["improvements"]["label1"][0].IssueSummary)
+	assert.Equal(t, "improvements", m.GValues.Categories["improvements"]["label1"][0].CategoryName)
+	assert.Equal(t, 1, len(m.GValues.Categories["security"]["label1"]))
+	assert.Equal(t, "SILK-2", m.GValues.Categories["security"]["label1"][0].IssueKey)
+	assert.Equal(t, 1, len(m.GValues.Categories["deprecations"]["label1"]))
+	assert.Equal(t, "SILK-3", m.GValues.Categories["deprecations"]["label1"][0].IssueKey)
+	assert.Empty(t, m.GValues.Features["label1"])
+	assert.Empty(t, m.GValues.Bugs["label1"])
+}
+
+func TestEnrichWithDefaultCategoryReplaced(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repoID", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{
+		{ParsedKey: "SILK-1", ParsedIssueTracker: "SILK", ParsedType: "perf", ParsedCategory: entities.IMPROVEMENT},
+		{ParsedKey: "SILK-2", ParsedIssueTracker: "SILK", ParsedType: "refactor", ParsedCategory: entities.IMPROVEMENT},
+	}, nil)
+
+	values := []byte("" +
+		"services:\n" +
+		"  - label: label1\n" +
+		"    gitRepoID: repoID\n" +
+		"    previousVersion: 0.0.0\n" +
+		"    version: 1.0.0\n")
+
+	m, err := model.New(values,
+		model.WithRepoService(mockRepoParser),
+		model.WithIssueTracker("SILK", nil),
+		model.WithCategory(model.Category{Name: "Improvements", CommitTypes: []string{"perf"}}))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	err = m.EnrichWithIssueTrackers()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(m.GValues.Categories["Improvements"]["label1"]))
+	assert.Equal(t, "SILK-1", m.GValues.Categories["Improvements"]["label1"][0].IssueKey)
+	assert.NotContains(t, m.GValues.Categories, "improvements")
+}
+
+func TestEnrichWithCategories(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repoID", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{
+		{ParsedKey: "AAA-1", ParsedIssueTracker: "JIRA", ParsedType: "feat"},
+		{ParsedKey: "AAA-2", ParsedIssueTracker: "JIRA", ParsedType: "feat"},
+		{ParsedKey: "AAA-3", ParsedIssueTracker: "JIRA", ParsedType: "feat"},
+		{ParsedKey: "SILK-1", ParsedIssueTracker: "SILK", ParsedType: "refactor", ParsedCategory: entities.IMPROVEMENT},
+		{ParsedKey: "SILK-2", ParsedIssueTracker: "SILK", ParsedType: "deprecate", ParsedCategory: entities.DEPRECATION},
+	}, nil)
+
+	mockIssueTracker := new(MockIssueTracker)
+	mockIssueTracker.On("GetIssues", []string{"AAA-1", "AAA-2", "AAA-3"}).Return([]entities.Issue{
+		{IssueKey: "AAA-1", IssueType: "Improvement", IssueStatus: "Done", Category: entities.CLOSED_FEATURE},
+		{IssueKey: "AAA-2", IssueType: "Story", IssueStatus: "Done", Category: entities.CLOSED_FEATURE, Labels: []string{"type::security"}},
+		{IssueKey: "AAA-3", IssueType: "Story", IssueStatus: "Done", Category: entities.CLOSED_FEATURE},
+	}, nil)
+	mockIssueTracker.On("GetKnownIssues", mock.Anything).Return([]entities.Issue{}, nil)
+
+	values := []byte("" +
+		"services:\n" +
+		"  - label: label1\n" +
+		"    gitRepoID: repoID\n" +
+		"    previousVersion: 0.0.0\n" +
+		"    version: 1.0.0\n")
+
+	m, err := model.New(values,
+		model.WithRepoService(mockRepoParser),
+		model.WithIssueTracker("JIRA", mockIssueTracker),
+		model.WithIssueTracker("SILK", nil),
+		model.WithCategory(model.Category{Name: "enhancements", IssueTypes: []string{"improvement:done"}}),
+		model.WithCategory(model.Category{Name: "hardening", Labels: []string{"type::sec*"}, Bump: model.BumpMinor}),
+		model.WithCategory(model.Category{Name: "technical", CommitTypes: []string{"refactor", "build"}}))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	err = m.EnrichWithIssueTrackers()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(m.GValues.Categories["enhancements"]["label1"]))
+	assert.Equal(t, "AAA-1", m.GValues.Categories["enhancements"]["label1"][0].IssueKey)
+	assert.Equal(t, "enhancements", m.GValues.Categories["enhancements"]["label1"][0].CategoryName)
+	assert.Equal(t, 1, len(m.GValues.Categories["hardening"]["label1"]))
+	assert.Equal(t, "AAA-2", m.GValues.Categories["hardening"]["label1"][0].IssueKey)
+	assert.Equal(t, 1, len(m.GValues.Categories["technical"]["label1"]))
+	assert.Equal(t, "SILK-1", m.GValues.Categories["technical"]["label1"][0].IssueKey)
+	assert.Equal(t, 1, len(m.GValues.Features["label1"]))
+	assert.Equal(t, "AAA-3", m.GValues.Features["label1"][0].IssueKey)
+	assert.Equal(t, 1, len(m.GValues.Categories["deprecations"]["label1"]))
+	assert.Empty(t, m.GValues.Categories["improvements"]["label1"])
+
+	b, err := m.Yaml()
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "\n  enhancements:\n    label1:\n")
+}
+
+func TestNewWithInvalidCategory(t *testing.T) {
+	values := []byte("services: []\n")
+
+	_, err := model.New(values, model.WithCategory(model.Category{Name: "features"}))
+	assert.Error(t, err)
+
+	_, err = model.New(values, model.WithCategory(model.Category{Name: "technical", Bump: "huge"}))
+	assert.Error(t, err)
+}
+
+func TestEnrichWithReverts(t *testing.T) {
+	mockRepoParser := new(MockRepoParser)
+	mockRepoParser.On("GetParsedRecords", "repoID", "0.0.0", "1.0.0", "").Return([]entities.ParsedRepoRecord{
+		{ParsedKey: "AAA-1", ParsedIssueTracker: "JIRA", ParsedCategory: entities.REVERT, ParsedType: "revert"},
+		{ParsedKey: "SILK-1", ParsedIssueTracker: "SILK", ParsedCategory: entities.REVERT, ParsedType: "revert", ParsedSummary: "old feature"},
+	}, nil)
+
+	mockIssueTracker := new(MockIssueTracker)
+	mockIssueTracker.On("GetIssues", []string{"AAA-1"}).Return([]entities.Issue{
+		{IssueKey: "AAA-1", Category: entities.CLOSED_FEATURE},
+	}, nil)
+	mockIssueTracker.On("GetKnownIssues", mock.Anything).Return([]entities.Issue{}, nil)
+
+	values := []byte("" +
+		"services:\n" +
+		"  - label: label1\n" +
+		"    gitRepoID: repoID\n" +
+		"    previousVersion: 0.0.0\n" +
+		"    version: 1.0.0\n")
+
+	m, err := model.New(values,
+		model.WithRepoService(mockRepoParser),
+		model.WithIssueTracker("JIRA", mockIssueTracker),
+		model.WithIssueTracker("SILK", nil))
+	assert.NoError(t, err)
+
+	err = m.EnrichWithRepos()
+	assert.NoError(t, err)
+
+	err = m.EnrichWithIssueTrackers()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(m.GValues.Reverts["label1"]))
+	assert.Equal(t, "AAA-1", m.GValues.Reverts["label1"][0].IssueKey)
+	assert.Equal(t, "old feature", m.GValues.Reverts["label1"][1].IssueSummary)
+	assert.Empty(t, m.GValues.Features["label1"])
+	assert.True(t, m.GValues.GitRepos[0].HasBugFixed)
+}
@@ -42,6 +42,27 @@ func (y *Yaml) GetValue(path string) (string, error) {
 	return result[0].Value, nil
 }
 
+// Find returns the nodes matching the path, each one as a Yaml on which further
+// paths can be evaluated.
+func (y *Yaml) Find(path string) ([]*Yaml, error) {
+	v, err := yamlpath.NewPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := v.Find(y.node)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]*Yaml, 0, len(result))
+	for _, n := range result {
+		found = append(found, &Yaml{node: n})
+	}
+
+	return found, nil
+}
+
 func (y *Yaml) Delete(key string) error {
 	if len := len(y.node.Content); len == 0 {
 		return nil
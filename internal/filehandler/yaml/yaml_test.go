@@ -241,3 +241,18 @@ a: hello
 		})
 	}
 }
+
+func TestFind(t *testing.T) {
+	y, err := yaml.NewYaml([]byte(`{"items": [{"id": "A-1", "fields": {"title": "first"}}, {"id": "A-2", "fields": {"title": "second"}}]}`))
+	assert.NoError(t, err)
+
+	items, err := y.Find("$.items[*]")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(items))
+
+	for i, expected := range []string{"first", "second"} {
+		title, err := items[i].GetValue("$.fields.title")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, title)
+	}
+}
@@ -0,0 +1,318 @@
+package issuetrackers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/happyagosmith/jig/internal/entities"
+	yamlfile "github.com/happyagosmith/jig/internal/filehandler/yaml"
+)
+
+// HTTPFields are the JSONPath expressions locating the issue fields in the
+// issue returned by the tracker.
+type HTTPFields struct {
+	Key     string `yaml:"key" mapstructure:"key"`
+	Summary string `yaml:"summary" mapstructure:"summary"`
+	Type    string `yaml:"type" mapstructure:"type"`
+	Status  string `yaml:"status" mapstructure:"status"`
+	URL     string `yaml:"url" mapstructure:"url"`
+}
+
+// defaultHTTPTimeout is the time limit of each request to the tracker, so that
+// a tracker not responding doesn't block the enrichment.
+const defaultHTTPTimeout = 30 * time.Second
+
+type httpCategoryRule struct {
+	issueType   string
+	issueStatus string
+	category    entities.IssueCategory
+}
+
+// HTTP retrieves the issues from a REST API returning JSON, with the issue
+// fields located by JSONPath expressions. The issues are retrieved one by one
+// with the issue URL or, when set, in batches with the batch URL.
+type HTTP struct {
+	client          *http.Client
+	timeout         time.Duration
+	issueURL        string
+	batchURL        string
+	issuesPath      string
+	batchSize       int
+	headers         map[string]string
+	fields          HTTPFields
+	categoryRules   []httpCategoryRule
+	defaultCategory entities.IssueCategory
+	browseURL       string
+}
+
+type HTTPOpt func(*HTTP)
+
+// WithHTTPClient sets the client of the requests, instead of a client with the
+// timeout of the tracker.
+func WithHTTPClient(c *http.Client) HTTPOpt {
+	return func(h *HTTP) {
+		h.client = c
+	}
+}
+
+// WithTimeout sets the time limit of each request, 30 seconds by default.
+func WithTimeout(d time.Duration) HTTPOpt {
+	return func(h *HTTP) {
+		if d > 0 {
+			h.timeout = d
+		}
+	}
+}
+
+// WithBatchURL sets the template of the URL retrieving several issues at once,
+// where {keys} is replaced by the keys separated by comma. The issues are
+// located in the response with the issuesPath JSONPath expression.
+func WithBatchURL(tpl, issuesPath string) HTTPOpt {
+	return func(h *HTTP) {
+		h.batchURL = tpl
+		h.issuesPath = issuesPath
+	}
+}
+
+// WithBatchSize sets the max number of keys retrieved with each call of the batch URL.
+func WithBatchSize(v int) HTTPOpt {
+	return func(h *HTTP) {
+		if v > 0 {
+			h.batchSize = v
+		}
+	}
+}
+
+// WithHeader adds a header, e.g. the authorization header, to each request.
+func WithHeader(name, value string) HTTPOpt {
+	return func(h *HTTP) {
+		h.headers[name] = value
+	}
+}
+
+// WithFields sets the JSONPath expressions locating the issue fields. The
+// expressions not set keep their default.
+func WithFields(f HTTPFields) HTTPOpt {
+	return func(h *HTTP) {
+		for _, p := range []struct{ from, to *string }{
+			{&f.Key, &h.fields.Key},
+			{&f.Summary, &h.fields.Summary},
+			{&f.Type, &h.fields.Type},
+			{&f.Status, &h.fields.Status},
+			{&f.URL, &h.fields.URL},
+		} {
+			if *p.from != "" {
+				*p.to = *p.from
+			}
+		}
+	}
+}
+
+// WithCategoryRule associates the issues of the type and status to the
+// category. An empty type or status matches any value. The rules are evaluated
+// in the order they are added.
+func WithCategoryRule(issueType, issueStatus string, category entities.IssueCategory) HTTPOpt {
+	return func(h *HTTP) {
+		h.categoryRules = append(h.categoryRules, httpCategoryRule{issueType: issueType, issueStatus: issueStatus, category: category})
+	}
+}
+
+// WithHTTPDefaultCategory sets the category of the issues not matching any rule.
+func WithHTTPDefaultCategory(category entities.IssueCategory) HTTPOpt {
+	return func(h *HTTP) {
+		h.defaultCategory = category
+	}
+}
+
+// WithHTTPBrowseURL sets the template of the issue web URL, where {key} is
+// replaced by the issue key, used when the issue has no URL.
+func WithHTTPBrowseURL(tpl string) HTTPOpt {
+	return func(h *HTTP) {
+		h.browseURL = tpl
+	}
+}
+
+// NewHTTP returns the tracker retrieving each issue from the issueURL template,
+// where {key} is replaced by the issue key.
+func NewHTTP(issueURL string, opts ...HTTPOpt) (HTTP, error) {
+	h := HTTP{
+		timeout:  defaultHTTPTimeout,
+		issueURL: issueURL,
+		headers:  map[string]string{},
+		fields: HTTPFields{
+			Key:     "$.key",
+			Summary: "$.summary",
+			Type:    "$.type",
+			Status:  "$.status",
+			URL:     "$.url",
+		},
+		batchSize:       defaultKeysBatchSize,
+		defaultCategory: entities.OTHER,
+	}
+	for _, o := range opts {
+		o(&h)
+	}
+	if h.client == nil {
+		h.client = &http.Client{Timeout: h.timeout}
+	}
+
+	if h.issueURL == "" && h.batchURL == "" {
+		return HTTP{}, fmt.Errorf("issue URL or batch URL is required")
+	}
+	if h.batchURL != "" && h.issuesPath == "" {
+		return HTTP{}, fmt.Errorf("issues path is required with the batch URL")
+	}
+
+	return h, nil
+}
+
+func (h HTTP) get(ctx context.Context, u string) (*yamlfile.Yaml, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range h.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, resp.StatusCode, fmt.Errorf("request %s failed with status %d", u, resp.StatusCode)
+	}
+
+	// the JSON is normalized before being read as yaml, to support any indentation
+	var v any
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("invalid JSON returned by %s: %w", u, err)
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	y, err := yamlfile.NewYaml(normalized)
+
+	return y, resp.StatusCode, err
+}
+
+func (h HTTP) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, keys []string) ([]entities.Issue, error) {
+	keys = uniqueKeys(keys)
+	if h.batchURL != "" {
+		return h.getIssuesInBatches(ctx, keys)
+	}
+
+	issues := make([]entities.Issue, 0, len(keys))
+	for _, key := range keys {
+		u := strings.ReplaceAll(h.issueURL, "{key}", url.PathEscape(key))
+		fmt.Printf("retrieving issue info using %s\n", u)
+		y, status, err := h.get(ctx, u)
+		if status == http.StatusNotFound {
+			fmt.Printf("issue %s not found\n", key)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the issue %s: %w", key, err)
+		}
+
+		issues = append(issues, h.toIssue(y, key))
+	}
+
+	return issues, nil
+}
+
+func (h HTTP) getIssuesInBatches(ctx context.Context, keys []string) ([]entities.Issue, error) {
+	var issues []entities.Issue
+	for len(keys) > 0 {
+		batch := keys[:min(h.batchSize, len(keys))]
+		keys = keys[len(batch):]
+
+		u := strings.ReplaceAll(h.batchURL, "{keys}", url.QueryEscape(strings.Join(batch, ",")))
+		fmt.Printf("retrieving issues info using %s\n", u)
+		y, _, err := h.get(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the issues: %w", err)
+		}
+
+		found, err := y.Find(h.issuesPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate the issues with %s: %w", h.issuesPath, err)
+		}
+		for _, i := range found {
+			issues = append(issues, h.toIssue(i, ""))
+		}
+	}
+
+	return issues, nil
+}
+
+// value returns the value located by the path, empty when not found.
+func value(y *yamlfile.Yaml, path string) string {
+	if path == "" {
+		return ""
+	}
+	v, err := y.GetValue(path)
+	if err != nil {
+		return ""
+	}
+
+	return v
+}
+
+// toIssue maps the issue located in y. The key is read with the key path when
+// not given, as for the issues retrieved in batches.
+func (h HTTP) toIssue(y *yamlfile.Yaml, key string) entities.Issue {
+	if key == "" {
+		key = value(y, h.fields.Key)
+	}
+	issue := entities.Issue{
+		IssueKey:     key,
+		IssueSummary: value(y, h.fields.Summary),
+		IssueType:    value(y, h.fields.Type),
+		IssueStatus:  value(y, h.fields.Status),
+		WebURL:       value(y, h.fields.URL),
+	}
+	if issue.WebURL == "" && h.browseURL != "" {
+		issue.WebURL = strings.ReplaceAll(h.browseURL, "{key}", key)
+	}
+	issue.Category = h.extractIssueCategory(issue)
+
+	return issue
+}
+
+func (h HTTP) extractIssueCategory(issue entities.Issue) entities.IssueCategory {
+	for _, r := range h.categoryRules {
+		if (r.issueType == "" || strings.EqualFold(r.issueType, issue.IssueType)) &&
+			(r.issueStatus == "" || strings.EqualFold(r.issueStatus, issue.IssueStatus)) {
+			return r.category
+		}
+	}
+
+	return h.defaultCategory
+}
+
+// IssueURLTemplate returns the template of the issue web URL, empty when not set.
+func (h HTTP) IssueURLTemplate() string {
+	return h.browseURL
+}
+
+func (h HTTP) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
+	return nil, nil
+}
@@ -0,0 +1,140 @@
+package issuetrackers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/happyagosmith/jig/internal/entities"
+	"github.com/happyagosmith/jig/internal/issuetrackers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPGetIssues(t *testing.T) {
+	var gotPaths, gotAuth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/issues/SILK-1":
+			fmt.Fprint(w, `{"id": "SILK-1", "fields": {"title": "first feature", "kind": "Story", "state": "Done"}, "links": {"self": "https://silk.example.com/SILK-1"}}`)
+		case "/issues/SILK-2":
+			fmt.Fprint(w, `{"id": "SILK-2", "fields": {"title": "first bug", "kind": "Defect", "state": "Open"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	h, err := issuetrackers.NewHTTP(srv.URL+"/issues/{key}",
+		issuetrackers.WithHeader("Authorization", "Bearer token"),
+		issuetrackers.WithFields(issuetrackers.HTTPFields{
+			Summary: "$.fields.title",
+			Type:    "$.fields.kind",
+			Status:  "$.fields.state",
+			URL:     "$.links.self",
+		}),
+		issuetrackers.WithCategoryRule("story", "done", entities.CLOSED_FEATURE),
+		issuetrackers.WithCategoryRule("defect", "", entities.FIXED_BUG))
+	require.NoError(t, err)
+
+	issues, err := h.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"SILK-1", "SILK-2", "SILK-3", "SILK-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/issues/SILK-1", "/issues/SILK-2", "/issues/SILK-3"}, gotPaths)
+	assert.Equal(t, []string{"Bearer token", "Bearer token", "Bearer token"}, gotAuth)
+	assert.Equal(t, []entities.Issue{
+		{IssueKey: "SILK-1", IssueSummary: "first feature", IssueType: "Story", IssueStatus: "Done", Category: entities.CLOSED_FEATURE, WebURL: "https://silk.example.com/SILK-1"},
+		{IssueKey: "SILK-2", IssueSummary: "first bug", IssueType: "Defect", IssueStatus: "Open", Category: entities.FIXED_BUG},
+	}, issues)
+}
+
+func TestHTTPGetIssuesInBatches(t *testing.T) {
+	var gotKeys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKeys = append(gotKeys, r.URL.Query().Get("keys"))
+		switch r.URL.Query().Get("keys") {
+		case "SILK-1,SILK-2":
+			fmt.Fprint(w, `{"issues": [{"key": "SILK-1", "summary": "first", "type": "story", "status": "done"}, {"key": "SILK-2", "summary": "second", "type": "task", "status": "done", "url": "https://silk.example.com/SILK-2"}]}`)
+		default:
+			fmt.Fprint(w, `{"issues": []}`)
+		}
+	}))
+	defer srv.Close()
+
+	h, err := issuetrackers.NewHTTP("",
+		issuetrackers.WithBatchURL(srv.URL+"/search?keys={keys}", "$.issues[*]"),
+		issuetrackers.WithBatchSize(2),
+		issuetrackers.WithHTTPBrowseURL("https://silk.example.com/browse/{key}"),
+		issuetrackers.WithCategoryRule("story", "", entities.CLOSED_FEATURE),
+		issuetrackers.WithHTTPDefaultCategory(entities.SUB_TASK))
+	require.NoError(t, err)
+
+	issues, err := h.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"SILK-1", "SILK-2", "SILK-3"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"SILK-1,SILK-2", "SILK-3"}, gotKeys)
+	assert.Equal(t, "https://silk.example.com/browse/{key}", h.IssueURLTemplate())
+	assert.Equal(t, []entities.Issue{
+		{IssueKey: "SILK-1", IssueSummary: "first", IssueType: "story", IssueStatus: "done", Category: entities.CLOSED_FEATURE, WebURL: "https://silk.example.com/browse/SILK-1"},
+		{IssueKey: "SILK-2", IssueSummary: "second", IssueType: "task", IssueStatus: "done", Category: entities.SUB_TASK, WebURL: "https://silk.example.com/SILK-2"},
+	}, issues)
+}
+
+func TestHTTPGetIssuesFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	h, err := issuetrackers.NewHTTP(srv.URL + "/issues/{key}")
+	require.NoError(t, err)
+
+	_, err = h.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"SILK-1"})
+	assert.Error(t, err)
+}
+
+func TestHTTPGetIssuesTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	h, err := issuetrackers.NewHTTP(srv.URL+"/issues/{key}", issuetrackers.WithTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = h.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"SILK-1"})
+	assert.ErrorContains(t, err, "Client.Timeout exceeded")
+}
+
+func TestHTTPGetIssuesWithClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"key": "SILK-1", "summary": "over TLS"}`)
+	}))
+	defer srv.Close()
+
+	h, err := issuetrackers.NewHTTP(srv.URL+"/issues/{key}", issuetrackers.WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	issues, err := h.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"SILK-1"})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "over TLS", issues[0].IssueSummary)
+}
+
+func TestNewHTTPValidation(t *testing.T) {
+	_, err := issuetrackers.NewHTTP("")
+	assert.Error(t, err)
+
+	_, err = issuetrackers.NewHTTP("", issuetrackers.WithBatchURL("https://silk.example.com/search?keys={keys}", ""))
+	assert.Error(t, err)
+}
@@ -2,26 +2,114 @@ package issuetrackers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"text/template"
 
-	v2 "github.com/ctreminiom/go-atlassian/jira/v3"
+	jirav2 "github.com/ctreminiom/go-atlassian/jira/v2"
+	jirav3 "github.com/ctreminiom/go-atlassian/jira/v3"
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/happyagosmith/jig/internal/entities"
 )
 
+// errFixVersionNotFound is returned by the searches rejected by Jira because
+// the JQL references a fixVersion that doesn't exist.
+var errFixVersionNotFound = errors.New("fixVersion not found")
+
+// isFixVersionNotFound reports whether the body of a bad request is the Jira
+// error of a missing fixVersion value, e.g. "The value '1.0.0' does not exist
+// for the field 'fixVersion'.".
+func isFixVersionNotFound(body []byte) bool {
+	var jiraErr struct {
+		ErrorMessages []string `json:"errorMessages"`
+	}
+	if err := json.Unmarshal(body, &jiraErr); err != nil {
+		return false
+	}
+	for _, m := range jiraErr.ErrorMessages {
+		if strings.Contains(strings.ToLower(m), "does not exist for the field 'fixversion'") {
+			return true
+		}
+	}
+
+	return false
+}
+
 type jiraFilter struct {
 	issueType   string
 	issueStatus string
 }
 
+const (
+	defaultSearchPageSize = 1000
+	defaultKeysBatchSize  = 100
+)
+
+const (
+	// CloudFlavour uses the REST API v3 of Jira Cloud with basic authentication.
+	CloudFlavour = "cloud"
+	// ServerFlavour uses the REST API v2 of Jira Server and Data Center with a
+	// personal access token, or basic authentication when the username is set.
+	ServerFlavour = "server"
+)
+
+// NormalizeFlavour returns the flavour of the Jira instance in lowercase, with
+// the datacenter alias of the server flavour resolved and cloud by default.
+func NormalizeFlavour(flavour string) string {
+	switch f := strings.ToLower(strings.TrimSpace(flavour)); f {
+	case "":
+		return CloudFlavour
+	case "datacenter":
+		return ServerFlavour
+	default:
+		return f
+	}
+}
+
+// jiraClient is implemented by the clients of both the REST API v2 and v3.
+type jiraClient interface {
+	NewRequest(ctx context.Context, method, urlStr, type_ string, body interface{}) (*http.Request, error)
+	Call(request *http.Request, structure interface{}) (*models.ResponseScheme, error)
+}
+
 type Jira struct {
-	client               *v2.Client
+	client               jiraClient
+	flavour              string
 	closedFeatureFilters []jiraFilter
 	fixedBugFilters      []jiraFilter
 	jqlKnownIssue        string
+	searchPageSize       int
+	keysBatchSize        int
+	browseURL            string
+	fieldMappings        []fieldMapping
+	fixVersion           string
+	epicRollUp           bool
+	epicLinkField        string
+}
+
+// searchResult is the page returned by the /search/jql endpoint, paginated by
+// nextPageToken, still exposing startAt and total of the previous search endpoint.
+type searchResult struct {
+	models.IssueSearchScheme
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	IsLast        bool   `json:"isLast,omitempty"`
+	rawFields     map[string]rawFields
+}
+
+// jiraIssue is the issue returned by the searches with the values of the
+// mapped fields and the key of the epic.
+type jiraIssue struct {
+	*models.IssueScheme
+	rawFields
+}
+
+// searchResultV2 is the page returned by the /rest/api/2/search endpoint of Jira Server.
+type searchResultV2 struct {
+	models.IssueSearchSchemeV2
 }
 
 type JiraOpt func(*Jira)
@@ -44,45 +132,275 @@ func WithKnownIssueJql(jql string) JiraOpt {
 	}
 }
 
-func NewJira(URL, username, password string, opts ...JiraOpt) (Jira, error) {
-	client, err := v2.New(nil, URL)
-	if err != nil {
-		return Jira{}, err
+// WithSearchPageSize sets the max number of issues requested for each page of the searches.
+func WithSearchPageSize(v int) JiraOpt {
+	return func(j *Jira) {
+		if v > 0 {
+			j.searchPageSize = v
+		}
+	}
+}
+
+// WithKeysBatchSize sets the max number of issue keys included in each search,
+// to keep the JQL within the length limits.
+func WithKeysBatchSize(v int) JiraOpt {
+	return func(j *Jira) {
+		if v > 0 {
+			j.keysBatchSize = v
+		}
+	}
+}
+
+// WithBrowseURL sets the template of the issue web URL, where {key} is replaced
+// by the issue key. When the template has no {key}, the key is appended.
+func WithBrowseURL(tpl string) JiraOpt {
+	return func(j *Jira) {
+		if tpl != "" {
+			j.browseURL = tpl
+		}
 	}
+}
+
+// WithFixVersion sets the default pattern of the fixVersion of the release
+// scope, where {version} is replaced by the version of the repo.
+func WithFixVersion(pattern string) JiraOpt {
+	return func(j *Jira) {
+		j.fixVersion = pattern
+	}
+}
+
+// WithEpicRollUp sets the epic of each issue as its parent, using the epic link
+// field, identified by id or name, or the parent field when not set.
+func WithEpicRollUp(epicLinkField string) JiraOpt {
+	return func(j *Jira) {
+		j.epicRollUp = true
+		j.epicLinkField = strings.TrimSpace(epicLinkField)
+	}
+}
 
-	client.Auth.SetBasicAuth(username, password)
+// WithFlavour sets the flavour of the Jira instance: cloud (default) or server
+// for Jira Server and Data Center.
+func WithFlavour(flavour string) JiraOpt {
+	return func(j *Jira) {
+		j.flavour = NormalizeFlavour(flavour)
+	}
+}
 
-	j := Jira{client: client}
+// NewJira returns the Jira tracker. With the server flavour and no username,
+// the password is used as personal access token.
+func NewJira(URL, username, password string, opts ...JiraOpt) (Jira, error) {
+	j := Jira{
+		flavour:        CloudFlavour,
+		searchPageSize: defaultSearchPageSize,
+		keysBatchSize:  defaultKeysBatchSize,
+		browseURL:      strings.TrimSuffix(URL, "/") + "/browse/{key}",
+	}
 	for _, o := range opts {
 		o(&j)
 	}
 
+	switch j.flavour {
+	case CloudFlavour:
+		client, err := jirav3.New(nil, URL)
+		if err != nil {
+			return Jira{}, err
+		}
+		client.Auth.SetBasicAuth(username, password)
+		j.client = client
+	case ServerFlavour:
+		client, err := jirav2.New(nil, URL)
+		if err != nil {
+			return Jira{}, err
+		}
+		if username == "" {
+			client.Auth.SetBearerToken(password)
+		} else {
+			client.Auth.SetBasicAuth(username, password)
+		}
+		j.client = client
+	default:
+		return Jira{}, fmt.Errorf("unsupported Jira flavour %q, expected cloud or server", j.flavour)
+	}
+
 	return j, nil
 }
 
-// searchIssuesRaw calls the new /rest/api/3/search/jql endpoint using raw API call
-func (j Jira) searchIssuesRaw(ctx context.Context, jql string, startAt, maxResults int) (*models.IssueSearchScheme, *models.ResponseScheme, error) {
-	// Build the query parameters
+// searchIssuesRaw calls the new /rest/api/3/search/jql endpoint using raw API call,
+// or the /rest/api/2/search endpoint with the server flavour
+func (j Jira) searchIssuesRaw(ctx context.Context, jql string, startAt, maxResults int, nextPageToken string) (*searchResult, *models.ResponseScheme, error) {
+	if j.flavour == ServerFlavour {
+		return j.searchIssuesRawV2(ctx, jql, startAt, maxResults)
+	}
+
+	params := j.searchParams(jql, startAt, maxResults)
+	if nextPageToken != "" {
+		params.Add("nextPageToken", nextPageToken)
+	}
+
+	apiEndpoint := fmt.Sprintf("rest/api/3/search/jql?%s", params.Encode())
+
+	request, err := j.client.NewRequest(ctx, http.MethodGet, apiEndpoint, "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(searchResult)
+	response, err := j.client.Call(request, result)
+	if err != nil {
+		return nil, response, err
+	}
+
+	result.rawFields, err = j.extractRawFields(response.Bytes.Bytes())
+	if err != nil {
+		return nil, response, err
+	}
+
+	return result, response, nil
+}
+
+func (j Jira) searchParams(jql string, startAt, maxResults int) url.Values {
 	params := url.Values{}
 	params.Add("jql", jql)
 	params.Add("startAt", fmt.Sprintf("%d", startAt))
 	params.Add("maxResults", fmt.Sprintf("%d", maxResults))
 	params.Add("fields", "*all")
+	if len(j.fieldMappings) > 0 || j.epicLinkField != "" {
+		params.Add("expand", "names")
+	}
 
-	apiEndpoint := fmt.Sprintf("rest/api/3/search/jql?%s", params.Encode())
+	return params
+}
+
+// searchIssuesRawV2 calls the /rest/api/2/search endpoint of Jira Server, converting
+// the issues to the v3 scheme used for both the flavours.
+func (j Jira) searchIssuesRawV2(ctx context.Context, jql string, startAt, maxResults int) (*searchResult, *models.ResponseScheme, error) {
+	params := j.searchParams(jql, startAt, maxResults)
+
+	apiEndpoint := fmt.Sprintf("rest/api/2/search?%s", params.Encode())
 
 	request, err := j.client.NewRequest(ctx, http.MethodGet, apiEndpoint, "", nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	searchResult := new(models.IssueSearchScheme)
-	response, err := j.client.Call(request, searchResult)
+	resultV2 := new(searchResultV2)
+	response, err := j.client.Call(request, resultV2)
+	if err != nil {
+		return nil, response, err
+	}
+
+	rawFields, err := j.extractRawFields(response.Bytes.Bytes())
 	if err != nil {
 		return nil, response, err
 	}
 
-	return searchResult, response, nil
+	result := &searchResult{IsLast: resultV2.StartAt+len(resultV2.Issues) >= resultV2.Total, rawFields: rawFields}
+	result.StartAt = resultV2.StartAt
+	result.MaxResults = resultV2.MaxResults
+	result.Total = resultV2.Total
+	for _, issue := range resultV2.Issues {
+		result.Issues = append(result.Issues, toIssueScheme(issue))
+	}
+
+	return result, response, nil
+}
+
+func toIssueScheme(issue *models.IssueSchemeV2) *models.IssueScheme {
+	converted := &models.IssueScheme{ID: issue.ID, Key: issue.Key, Self: issue.Self, Fields: &models.IssueFieldsScheme{}}
+	if f := issue.Fields; f != nil {
+		converted.Fields.Summary = f.Summary
+		converted.Fields.IssueType = f.IssueType
+		converted.Fields.Status = f.Status
+		converted.Fields.Parent = f.Parent
+		converted.Fields.FixVersions = f.FixVersions
+		converted.Fields.Components = f.Components
+		converted.Fields.Labels = f.Labels
+	}
+
+	return converted
+}
+
+// searchIssues returns the issues of all the pages matching the jql, following
+// nextPageToken or, when the token is not returned, startAt and total.
+func (j Jira) searchIssues(ctx context.Context, jql string) ([]jiraIssue, error) {
+	var issues []jiraIssue
+	startAt, nextPageToken := 0, ""
+	for {
+		result, response, err := j.searchIssuesRaw(ctx, jql, startAt, j.searchPageSize, nextPageToken)
+		if err != nil {
+			if response != nil {
+				fmt.Printf("Error response from Jira: endpoint=%s, status=%d\n", response.Endpoint, response.Code)
+				if response.Code == http.StatusBadRequest {
+					body := response.Bytes.Bytes()
+					if isFixVersionNotFound(body) {
+						return nil, fmt.Errorf("%w: %w", errFixVersionNotFound, err)
+					}
+					return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(body)))
+				}
+			}
+			return nil, err
+		}
+		for _, issue := range result.Issues {
+			issues = append(issues, jiraIssue{IssueScheme: issue, rawFields: result.rawFields[issue.Key]})
+		}
+
+		switch {
+		case result.NextPageToken != "" && result.NextPageToken != nextPageToken:
+			nextPageToken = result.NextPageToken
+		case !result.IsLast && len(result.Issues) > 0 && startAt+len(result.Issues) < result.Total:
+			startAt += len(result.Issues)
+		default:
+			return issues, nil
+		}
+	}
+}
+
+// searchIssuesByKeys returns the issues of the keys, searched in batches of keysBatchSize keys.
+func (j Jira) searchIssuesByKeys(ctx context.Context, keys []string) ([]jiraIssue, error) {
+	var issues []jiraIssue
+	for len(keys) > 0 {
+		batch := keys[:min(j.keysBatchSize, len(keys))]
+		keys = keys[len(batch):]
+
+		jql := fmt.Sprintf("issue in (%s)", strings.Join(batch, ","))
+		fmt.Printf("retrieving issues info using JQL \"%s\"\n", jql)
+		found, err := j.searchIssues(ctx, jql)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+
+	return issues, nil
+}
+
+func (j Jira) toIssue(issue jiraIssue) entities.Issue {
+	return entities.Issue{
+		Category:     j.extractIssueCategory(issue.IssueScheme),
+		IssueKey:     issue.Key,
+		IssueSummary: issue.Fields.Summary,
+		IssueType:    issue.Fields.IssueType.Name,
+		IssueStatus:  issue.Fields.Status.Name,
+		WebURL:       j.webURL(issue.Key),
+		Fields:       issue.mappedFields,
+	}
+}
+
+func (j Jira) webURL(key string) string {
+	if !strings.Contains(j.browseURL, "{key}") {
+		return j.browseURL + key
+	}
+
+	return strings.ReplaceAll(j.browseURL, "{key}", key)
+}
+
+// IssueURLTemplate returns the template of the issue web URL.
+func (j Jira) IssueURLTemplate() string {
+	if !strings.Contains(j.browseURL, "{key}") {
+		return j.browseURL + "{key}"
+	}
+
+	return j.browseURL
 }
 
 func (j Jira) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, keys []string) ([]entities.Issue, error) {
@@ -90,74 +408,128 @@ func (j Jira) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, keys [
 		return []entities.Issue{}, nil
 	}
 
-	jql := fmt.Sprintf("issue in (%s)", strings.Join(keys, ","))
-	fmt.Printf("retrieving issues info using JQL \"%s\"\n", jql)
-
-	searchResult, response, err := j.searchIssuesRaw(ctx, jql, 0, 1000)
+	found, err := j.searchIssuesByKeys(ctx, uniqueKeys(keys))
 	if err != nil {
-		if response != nil {
-			fmt.Printf("Error response from Jira: endpoint=%s, status=%d\n", response.Endpoint, response.Code)
-		}
 		return nil, fmt.Errorf("failed to search issues: %w", err)
 	}
 
-	issues := make([]entities.Issue, 0, len(searchResult.Issues))
+	selected, err := j.replaceSubtasks(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]entities.Issue, 0, len(selected))
+	for _, issue := range selected {
+		issues = append(issues, j.toIssue(issue))
+	}
+	if !j.epicRollUp {
+		return issues, nil
+	}
+
+	return j.rollUpEpics(ctx, selected, issues)
+}
+
+// replaceSubtasks returns the issues with the subtasks replaced by their
+// parents, retrieved when not already found.
+func (j Jira) replaceSubtasks(ctx context.Context, found []jiraIssue) ([]jiraIssue, error) {
+	selected := make([]jiraIssue, 0, len(found))
 	isPresent := map[string]bool{}
 
 	subTaskParents := []string{}
-	for _, issue := range searchResult.Issues {
+	for _, issue := range found {
 		if isPresent[issue.Key] {
 			continue
 		}
 		isPresent[issue.Key] = true
 		parent := issue.Fields.Parent
-		if issue.Fields.IssueType.Subtask && parent != nil && parent.Key != "" && !isPresent[parent.Key] {
+		if issue.Fields.IssueType != nil && issue.Fields.IssueType.Subtask && parent != nil && parent.Key != "" && !isPresent[parent.Key] {
 			fmt.Printf("issue %s is a subtask. add parent key %s instead\n", issue.Key, parent.Key)
 			subTaskParents = append(subTaskParents, parent.Key)
 			continue
 		}
 
-		issues = append(issues, entities.Issue{
-			Category:     j.extractIssueCategory(issue),
-			IssueKey:     issue.Key,
-			IssueSummary: issue.Fields.Summary,
-			IssueType:    issue.Fields.IssueType.Name,
-			IssueStatus:  issue.Fields.Status.Name,
-			WebURL:       fmt.Sprintf("/browse/%s", issue.Key),
-		})
+		selected = append(selected, issue)
 	}
-	if len(subTaskParents) == 0 {
-		return issues, nil
+
+	if len(subTaskParents) > 0 {
+		fmt.Printf("retrieving issue parents info\n")
+		parents, err := j.searchIssuesByKeys(ctx, uniqueKeys(subTaskParents))
+		if err != nil {
+			return nil, fmt.Errorf("failed to search parent issues: %w", err)
+		}
+
+		for _, issue := range parents {
+			if isPresent[issue.Key] {
+				continue
+			}
+			isPresent[issue.Key] = true
+			selected = append(selected, issue)
+		}
 	}
 
-	jql = fmt.Sprintf("issue in (%s)", strings.Join(subTaskParents, ","))
-	fmt.Printf("retrieving issue parents info using JQL \"%s\"\n", jql)
+	return selected, nil
+}
 
-	parentSearchResult, response, err := j.searchIssuesRaw(ctx, jql, 0, 1000)
-	if err != nil {
-		if response != nil {
-			fmt.Printf("Error response from Jira: endpoint=%s, status=%d\n", response.Endpoint, response.Code)
+// epicKey returns the key of the epic of the issue, read from the epic link
+// field when configured, or from the parent field of the issues not subtasks.
+func (j Jira) epicKey(issue jiraIssue) string {
+	if j.epicLinkField != "" {
+		return issue.epicKey
+	}
+	if issue.Fields.IssueType != nil && issue.Fields.IssueType.Subtask {
+		return ""
+	}
+	if issue.Fields.Parent == nil {
+		return ""
+	}
+
+	return issue.Fields.Parent.Key
+}
+
+// rollUpEpics retrieves the epics of the issues and sets them as parent.
+func (j Jira) rollUpEpics(ctx context.Context, found []jiraIssue, issues []entities.Issue) ([]entities.Issue, error) {
+	var epicKeys []string
+	for _, issue := range found {
+		if k := j.epicKey(issue); k != "" {
+			epicKeys = append(epicKeys, k)
 		}
-		return nil, fmt.Errorf("failed to search parent issues: %w", err)
+	}
+	if len(epicKeys) == 0 {
+		return issues, nil
 	}
 
-	for _, issue := range parentSearchResult.Issues {
-		if isPresent[issue.Key] {
-			continue
+	fmt.Printf("retrieving epics info\n")
+	epics, err := j.searchIssuesByKeys(ctx, uniqueKeys(epicKeys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search epics: %w", err)
+	}
+	epicByKey := map[string]entities.Issue{}
+	for _, e := range epics {
+		epicByKey[e.Key] = j.toIssue(e)
+	}
+
+	for i, issue := range found {
+		if epic, ok := epicByKey[j.epicKey(issue)]; ok {
+			issues[i].Parent = &epic
 		}
-		isPresent[issue.Key] = true
-		issues = append(issues, entities.Issue{
-			Category:     j.extractIssueCategory(issue),
-			IssueKey:     issue.Key,
-			IssueSummary: issue.Fields.Summary,
-			IssueType:    issue.Fields.IssueType.Name,
-			IssueStatus:  issue.Fields.Status.Name,
-			WebURL:       fmt.Sprintf("/browse/%s", issue.Key),
-		})
 	}
+
 	return issues, nil
 }
 
+func uniqueKeys(keys []string) []string {
+	isPresent := map[string]bool{}
+	unique := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !isPresent[k] {
+			isPresent[k] = true
+			unique = append(unique, k)
+		}
+	}
+
+	return unique
+}
+
 func (j Jira) extractIssueCategory(issue *models.IssueScheme) entities.IssueCategory {
 	if issue.Fields.IssueType.Subtask {
 		return entities.SUB_TASK
@@ -178,16 +550,28 @@ func (j Jira) extractIssueCategory(issue *models.IssueScheme) entities.IssueCate
 	return entities.OTHER
 }
 
+// GetKnownIssues retrieves the known issues with the known issues JQL of the
+// repo, or the default one, restricted to the project and component of the repo.
+// The JQL is a template evaluated with the fields of the repo.
 func (j Jira) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
-	if repo.Project == "" {
+	if repo.Project == "" && repo.KnownIssuesJQL == "" {
 		return nil, nil
 	}
 	component := repo.Component
 	project := repo.Project
 
+	tpl := repo.KnownIssuesJQL
+	if tpl == "" {
+		tpl = j.jqlKnownIssue
+	}
+	knownIssuesJQL, err := evaluateJQL(tpl, repo)
+	if err != nil {
+		return nil, err
+	}
+
 	jqls := []string{}
-	if j.jqlKnownIssue != "" {
-		jqls = append(jqls, j.jqlKnownIssue)
+	if knownIssuesJQL != "" {
+		jqls = append(jqls, knownIssuesJQL)
 	}
 
 	if project != "" {
@@ -205,24 +589,100 @@ func (j Jira) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) (
 	jql := strings.Join(jqls, " and ")
 	fmt.Printf("\nretrieving known issues using Jira jql \"%s\"\n", jql)
 
-	searchResult, response, err := j.searchIssuesRaw(ctx, jql, 0, 1000)
+	found, err := j.searchIssues(ctx, jql)
 	if err != nil {
-		if response != nil {
-			fmt.Printf("Error response from Jira: endpoint=%s, status=%d\n", response.Endpoint, response.Code)
-		}
 		return nil, fmt.Errorf("failed to search known issues: %w", err)
 	}
 
-	issues := make([]entities.Issue, 0, len(searchResult.Issues))
-	for _, issue := range searchResult.Issues {
-		issues = append(issues, entities.Issue{
-			Category:     j.extractIssueCategory(issue),
-			IssueKey:     issue.Key,
-			IssueSummary: issue.Fields.Summary,
-			IssueType:    issue.Fields.IssueType.Name,
-			IssueStatus:  issue.Fields.Status.Name,
-			WebURL:       fmt.Sprintf("/browse/%s", issue.Key),
-		})
+	issues := make([]entities.Issue, 0, len(found))
+	for _, issue := range found {
+		issues = append(issues, j.toIssue(issue))
+	}
+	return issues, nil
+}
+
+// GetScopeIssues retrieves the issues of the project of the repo having as
+// fixVersion the version of the repo, formatted with the fixVersion pattern of
+// the repo or the default one, with the subtasks replaced by their parents as
+// in GetIssues. Nothing is retrieved when no pattern or no project is
+// configured, while a fixVersion not existing yet gives an empty scope.
+func (j Jira) GetScopeIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
+	pattern := repo.FixVersion
+	if pattern == "" {
+		pattern = j.fixVersion
+	}
+	if pattern == "" || repo.ToTag == "" {
+		return nil, nil
+	}
+	if repo.Project == "" {
+		fmt.Printf("release scope of the repo \"%s\" not retrieved: jiraProject is required\n", repo.Label)
+		return nil, nil
+	}
+
+	fixVersion := strings.ReplaceAll(pattern, "{version}", repo.ToTag)
+	jql := fmt.Sprintf("project = \"%s\" and fixVersion = \"%s\"", repo.Project, fixVersion)
+	fmt.Printf("retrieving release scope using Jira jql \"%s\"\n", jql)
+
+	found, err := j.searchIssues(ctx, jql)
+	if errors.Is(err, errFixVersionNotFound) {
+		fmt.Printf("fixVersion \"%s\" not found, the release scope is empty\n", fixVersion)
+		return []entities.Issue{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the release scope: %w", err)
+	}
+
+	selected, err := j.replaceSubtasks(ctx, found)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the release scope: %w", err)
+	}
+
+	issues := make([]entities.Issue, 0, len(selected))
+	for _, issue := range selected {
+		issues = append(issues, j.toIssue(issue))
 	}
 	return issues, nil
 }
+
+// evaluateJQL evaluates the JQL template with the fields of the repo, named as
+// in the model: label, serviceName, gitRepoID, version, previousVersion,
+// jiraProject, jiraComponent and customAttributes. The quote function returns
+// a value as a JQL string, e.g. {{ quote .version }}.
+func evaluateJQL(tpl string, repo *entities.EnrichedRepo) (string, error) {
+	if !strings.Contains(tpl, "{{") {
+		return tpl, nil
+	}
+
+	t, err := template.New("jql").Option("missingkey=error").Funcs(template.FuncMap{"quote": quoteJQL}).Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the JQL template %q: %w", tpl, err)
+	}
+
+	customAttributes := repo.CustomAttributes
+	if customAttributes == nil {
+		customAttributes = map[string]any{}
+	}
+	var sb strings.Builder
+	err = t.Execute(&sb, map[string]any{
+		"label":            repo.Label,
+		"serviceName":      repo.ServiceName,
+		"gitRepoID":        repo.ID,
+		"version":          repo.ToTag,
+		"previousVersion":  repo.FromTag,
+		"jiraProject":      repo.Project,
+		"jiraComponent":    repo.Component,
+		"customAttributes": customAttributes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate the JQL template %q for the repo %s: %w", tpl, repo.Label, err)
+	}
+
+	return sb.String(), nil
+}
+
+// quoteJQL returns the value as a double-quoted JQL string, escaping the
+// backslashes and the double quotes.
+func quoteJQL(value any) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(fmt.Sprint(value))
+	return `"` + escaped + `"`
+}
@@ -0,0 +1,146 @@
+package issuetrackers
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// fieldMapping maps the Jira field, identified by id or by name, to the name
+// used in the fields of the issue.
+type fieldMapping struct {
+	name  string
+	field string
+}
+
+// WithField adds the value of the Jira field, identified by id (e.g.
+// customfield_10010, priority) or by name (e.g. Release Note), to the fields
+// of the issues with the given name.
+func WithField(name, field string) JiraOpt {
+	return func(j *Jira) {
+		j.fieldMappings = append(j.fieldMappings, fieldMapping{name: strings.TrimSpace(name), field: strings.TrimSpace(field)})
+	}
+}
+
+type rawSearchResult struct {
+	Names  map[string]string `json:"names"`
+	Issues []struct {
+		Key    string         `json:"key"`
+		Fields map[string]any `json:"fields"`
+	} `json:"issues"`
+}
+
+// rawFields holds the values read from the raw fields of an issue.
+type rawFields struct {
+	mappedFields map[string]any
+	epicKey      string
+}
+
+// extractRawFields returns the values of the mapped fields and the epic link
+// of each issue of the search response, indexed by issue key.
+func (j Jira) extractRawFields(body []byte) (map[string]rawFields, error) {
+	if len(j.fieldMappings) == 0 && j.epicLinkField == "" {
+		return nil, nil
+	}
+
+	var raw rawSearchResult
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	extracted := map[string]rawFields{}
+	for _, issue := range raw.Issues {
+		var rf rawFields
+		for _, fm := range j.fieldMappings {
+			id := fm.fieldID(raw.Names)
+			if v := fieldValue(issue.Fields[id]); v != nil {
+				if rf.mappedFields == nil {
+					rf.mappedFields = map[string]any{}
+				}
+				rf.mappedFields[fm.name] = v
+			}
+		}
+		if j.epicLinkField != "" {
+			id := fieldMapping{field: j.epicLinkField}.fieldID(raw.Names)
+			rf.epicKey, _ = fieldValue(issue.Fields[id]).(string)
+		}
+		extracted[issue.Key] = rf
+	}
+
+	return extracted, nil
+}
+
+func (fm fieldMapping) fieldID(names map[string]string) string {
+	if _, ok := names[fm.field]; ok {
+		return fm.field
+	}
+	for id, name := range names {
+		if strings.EqualFold(name, fm.field) {
+			return id
+		}
+	}
+
+	return fm.field
+}
+
+// fieldValue simplifies the value of a Jira field for the templates: options,
+// users, versions and components are reduced to their name and the rich text
+// documents to their plain text.
+func fieldValue(v any) any {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		if len(value) == 0 {
+			return nil
+		}
+		values := make([]any, 0, len(value))
+		for _, e := range value {
+			if ev := fieldValue(e); ev != nil {
+				values = append(values, ev)
+			}
+		}
+		return values
+	case map[string]any:
+		if value["type"] == "doc" {
+			return strings.TrimSpace(documentText(value))
+		}
+		for _, k := range []string{"value", "name", "displayName", "key"} {
+			if s, ok := value[k].(string); ok {
+				return s
+			}
+		}
+		return value
+	case string:
+		if value == "" {
+			return nil
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// documentText returns the text of an Atlassian Document Format node, with a
+// line for each paragraph.
+func documentText(node map[string]any) string {
+	if text, ok := node["text"].(string); ok {
+		return text
+	}
+	if node["type"] == "hardBreak" {
+		return "\n"
+	}
+
+	var sb strings.Builder
+	content, _ := node["content"].([]any)
+	for _, c := range content {
+		if child, ok := c.(map[string]any); ok {
+			sb.WriteString(documentText(child))
+		}
+	}
+	switch node["type"] {
+	case "paragraph", "heading", "listItem", "codeBlock":
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -2,9 +2,11 @@ package issuetrackers_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/happyagosmith/jig/internal/entities"
@@ -162,3 +164,366 @@ func TestJira(t *testing.T) {
 	})
 
 }
+
+func TestJiraGetIssuesBatchesAndPages(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		queries = append(queries, jql)
+
+		keys := strings.Split(strings.TrimSuffix(strings.TrimPrefix(jql, "issue in ("), ")"), ",")
+		page := keys[:1]
+		token := "next"
+		if r.URL.Query().Get("nextPageToken") == "next" {
+			page, token = keys[1:], ""
+		}
+
+		var issues []string
+		for _, k := range page {
+			issues = append(issues, fmt.Sprintf(`{"key": %q, "fields": {"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"}}}`, k))
+		}
+		fmt.Fprintf(w, `{"issues": [%s], "nextPageToken": %q, "isLast": %t}`, strings.Join(issues, ","), token, token == "")
+	}))
+	defer srv.Close()
+
+	jira, err := issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword",
+		issuetrackers.WithClosedFeatureFilter("STORY", "GOLIVE"),
+		issuetrackers.WithKeysBatchSize(2))
+	assert.NoError(t, err, "NewJira error must be nil")
+
+	issues, err := jira.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"AAA-1", "AAA-2", "AAA-3", "AAA-1", "AAA-4"})
+	assert.NoError(t, err, "GetIssues error must be nil")
+
+	assert.Equal(t, []string{
+		"issue in (AAA-1,AAA-2)", "issue in (AAA-1,AAA-2)",
+		"issue in (AAA-3,AAA-4)", "issue in (AAA-3,AAA-4)",
+	}, queries)
+
+	var keys []string
+	for _, i := range issues {
+		keys = append(keys, i.IssueKey)
+		assert.Equal(t, entities.CLOSED_FEATURE, i.Category)
+	}
+	assert.Equal(t, []string{"AAA-1", "AAA-2", "AAA-3", "AAA-4"}, keys)
+}
+
+func TestJiraGetKnownIssuesStartAtPagination(t *testing.T) {
+	var startAts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startAt := r.URL.Query().Get("startAt")
+		startAts = append(startAts, startAt)
+		fmt.Fprintf(w, `{"startAt": %s, "total": 3, "issues": [{"key": "AAA-%s", "fields": {"issuetype": {"name": "Bug"}, "status": {"name": "OPEN"}}}]}`, startAt, startAt)
+	}))
+	defer srv.Close()
+
+	jira, err := issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword", issuetrackers.WithSearchPageSize(1))
+	assert.NoError(t, err, "NewJira error must be nil")
+
+	issues, err := jira.GetKnownIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{Project: "TEST"}})
+	assert.NoError(t, err, "GetKnownIssues error must be nil")
+	assert.Equal(t, []string{"0", "1", "2"}, startAts)
+	assert.Equal(t, 3, len(issues))
+}
+
+func TestJiraIssueWebURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"issues": [{"key": "AAA-1", "fields": {"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"}}}]}`)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		browseURL   string
+		expected    string
+		expectedTpl string
+	}{
+		{name: "default", browseURL: "", expected: srv.URL + "/browse/AAA-1", expectedTpl: srv.URL + "/browse/{key}"},
+		{name: "template", browseURL: "https://proxy.example.com/jira/browse/{key}?focus=true", expected: "https://proxy.example.com/jira/browse/AAA-1?focus=true", expectedTpl: "https://proxy.example.com/jira/browse/{key}?focus=true"},
+		{name: "prefix", browseURL: "https://proxy.example.com/jira/browse/", expected: "https://proxy.example.com/jira/browse/AAA-1", expectedTpl: "https://proxy.example.com/jira/browse/{key}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jira, err := issuetrackers.NewJira(srv.URL+"/", "jiraUsername", "jiraPassword", issuetrackers.WithBrowseURL(tt.browseURL))
+			assert.NoError(t, err, "NewJira error must be nil")
+
+			issues, err := jira.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"AAA-1"})
+			assert.NoError(t, err, "GetIssues error must be nil")
+			assert.Equal(t, tt.expected, issues[0].WebURL)
+			assert.Equal(t, tt.expectedTpl, jira.IssueURLTemplate())
+		})
+	}
+}
+
+func TestJiraServerFlavour(t *testing.T) {
+	var gotRequests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequests = append(gotRequests, r)
+		if r.URL.Path != "/rest/api/2/search" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"startAt": 0, "total": 2, "issues": [
+			{"key": "AAA-1", "fields": {"issuetype": {"name": "Bug"}, "status": {"name": "FIXED"}, "summary": "a bug", "description": "plain text description"}},
+			{"key": "BBB-1", "fields": {"issuetype": {"name": "Sub-task", "subtask": true}, "status": {"name": "DONE"}, "parent": {"key": "AAA-2"}}}
+		]}`)
+	}))
+	defer srv.Close()
+
+	jira, err := issuetrackers.NewJira(srv.URL, "", "personalAccessToken",
+		issuetrackers.WithFlavour("server"),
+		issuetrackers.WithFixedBugFilter("BUG", "FIXED"),
+		issuetrackers.WithKnownIssueJql("key=value"))
+	assert.NoError(t, err, "NewJira error must be nil")
+
+	issues, err := jira.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"AAA-1", "BBB-1"})
+	assert.NoError(t, err, "GetIssues error must be nil")
+
+	assert.Equal(t, 2, len(gotRequests))
+	assert.Equal(t, "Bearer personalAccessToken", gotRequests[0].Header.Get("Authorization"))
+	assert.Equal(t, "issue in (AAA-1,BBB-1)", gotRequests[0].URL.Query().Get("jql"))
+	assert.Equal(t, "issue in (AAA-2)", gotRequests[1].URL.Query().Get("jql"))
+	assert.Equal(t, 1, len(issues))
+	assert.Equal(t, "AAA-1", issues[0].IssueKey)
+	assert.Equal(t, "a bug", issues[0].IssueSummary)
+	assert.Equal(t, entities.FIXED_BUG, issues[0].Category)
+
+	issues, err = jira.GetKnownIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{Project: "TEST"}})
+	assert.NoError(t, err, "GetKnownIssues error must be nil")
+	assert.Equal(t, "key=value and project = \"TEST\"", gotRequests[2].URL.Query().Get("jql"))
+	assert.Equal(t, 2, len(issues))
+}
+
+func TestNormalizeFlavour(t *testing.T) {
+	assert.Equal(t, issuetrackers.CloudFlavour, issuetrackers.NormalizeFlavour(""))
+	assert.Equal(t, issuetrackers.CloudFlavour, issuetrackers.NormalizeFlavour("Cloud"))
+	assert.Equal(t, issuetrackers.ServerFlavour, issuetrackers.NormalizeFlavour("server"))
+	assert.Equal(t, issuetrackers.ServerFlavour, issuetrackers.NormalizeFlavour("datacenter"))
+	assert.Equal(t, "unknown", issuetrackers.NormalizeFlavour("unknown"))
+}
+
+func TestJiraUnsupportedFlavour(t *testing.T) {
+	_, err := issuetrackers.NewJira("https://jira.example.com", "jiraUsername", "jiraPassword", issuetrackers.WithFlavour("unknown"))
+	assert.Error(t, err)
+}
+
+func TestJiraFields(t *testing.T) {
+	var gotRequest *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequest = r
+		fmt.Fprint(w, `{
+			"names": {"customfield_10010": "Release Note", "priority": "Priority", "components": "Component/s", "labels": "Labels", "fixVersions": "Fix Version/s"},
+			"issues": [{"key": "AAA-1", "fields": {
+				"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"},
+				"customfield_10010": {"type": "doc", "version": 1, "content": [
+					{"type": "paragraph", "content": [{"type": "text", "text": "first line"}]},
+					{"type": "paragraph", "content": [{"type": "text", "text": "second "}, {"type": "text", "text": "line"}]}
+				]},
+				"priority": {"id": "3", "name": "Medium"},
+				"components": [{"id": "1", "name": "backend"}, {"id": "2", "name": "frontend"}],
+				"labels": ["customer", "api"],
+				"fixVersions": [],
+				"customfield_10011": {"id": "10", "value": "Yes"}
+			}}]
+		}`)
+	}))
+	defer srv.Close()
+
+	jira, err := issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword",
+		issuetrackers.WithField("releaseNote", "release note"),
+		issuetrackers.WithField("priority", "priority"),
+		issuetrackers.WithField("components", "Component/s"),
+		issuetrackers.WithField("labels", "labels"),
+		issuetrackers.WithField("fixVersions", "fixVersions"),
+		issuetrackers.WithField("customerFacing", "customfield_10011"))
+	assert.NoError(t, err, "NewJira error must be nil")
+
+	issues, err := jira.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"AAA-1"})
+	assert.NoError(t, err, "GetIssues error must be nil")
+
+	assert.Equal(t, "names", gotRequest.URL.Query().Get("expand"))
+	assert.Equal(t, map[string]any{
+		"releaseNote":    "first line\nsecond line",
+		"priority":       "Medium",
+		"components":     []any{"backend", "frontend"},
+		"labels":         []any{"customer", "api"},
+		"customerFacing": "Yes",
+	}, issues[0].Fields)
+}
+
+func TestJiraGetScopeIssues(t *testing.T) {
+	var gotJQLs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		gotJQLs = append(gotJQLs, jql)
+		switch jql {
+		case "project = \"AAA\" and fixVersion = \"1.0.0\"":
+			fmt.Fprint(w, `{"issues": [
+				{"key": "AAA-1", "fields": {"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"}}},
+				{"key": "AAA-3", "fields": {"issuetype": {"name": "Sub-task", "subtask": true}, "parent": {"key": "AAA-2"}}}
+			]}`)
+		case "issue in (AAA-2)":
+			fmt.Fprint(w, `{"issues": [{"key": "AAA-2", "fields": {"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"}}}]}`)
+		case "project = \"BBB\" and fixVersion = \"1.0.0\"":
+			http.Error(w, `{"errorMessages": ["The value 'BBB' does not exist for the field 'project'."]}`, http.StatusBadRequest)
+		default:
+			http.Error(w, `{"errorMessages": ["The value 'service-2.0.0' does not exist for the field 'fixVersion'."]}`, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	jira, err := issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword", issuetrackers.WithFixVersion("{version}"))
+	assert.NoError(t, err, "NewJira error must be nil")
+
+	issues, err := jira.GetScopeIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ToTag: "1.0.0", Project: "AAA"}})
+	assert.NoError(t, err, "GetScopeIssues error must be nil")
+	assert.Equal(t, []string{"project = \"AAA\" and fixVersion = \"1.0.0\"", "issue in (AAA-2)"}, gotJQLs)
+	assert.Len(t, issues, 2)
+	assert.Equal(t, "AAA-1", issues[0].IssueKey)
+	assert.Equal(t, "AAA-2", issues[1].IssueKey)
+
+	gotJQLs = nil
+	issues, err = jira.GetScopeIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ToTag: "2.0.0", Project: "AAA", FixVersion: "service-{version}"}})
+	assert.NoError(t, err, "GetScopeIssues error must be nil")
+	assert.Equal(t, []string{"project = \"AAA\" and fixVersion = \"service-2.0.0\""}, gotJQLs)
+	assert.NotNil(t, issues)
+	assert.Empty(t, issues)
+
+	issues, err = jira.GetScopeIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ToTag: "1.0.0", Project: "BBB"}})
+	assert.ErrorContains(t, err, "does not exist for the field 'project'")
+	assert.Nil(t, issues)
+
+	gotJQLs = nil
+	issues, err = jira.GetScopeIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ToTag: "1.0.0"}})
+	assert.NoError(t, err, "GetScopeIssues error must be nil")
+	assert.Nil(t, gotJQLs)
+	assert.Nil(t, issues)
+
+	jira, err = issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword")
+	assert.NoError(t, err, "NewJira error must be nil")
+
+	issues, err = jira.GetScopeIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ToTag: "1.0.0", Project: "AAA"}})
+	assert.NoError(t, err, "GetScopeIssues error must be nil")
+	assert.Nil(t, issues)
+}
+
+func TestJiraEpicRollUp(t *testing.T) {
+	issues := map[string]string{
+		"AAA-1":  `{"key": "AAA-1", "fields": {"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"}, "parent": {"key": "EPIC-1"}}}`,
+		"AAA-2":  `{"key": "AAA-2", "fields": {"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"}, "customfield_10014": "EPIC-2"}}`,
+		"AAA-3":  `{"key": "AAA-3", "fields": {"issuetype": {"name": "Story"}, "status": {"name": "GOLIVE"}}}`,
+		"EPIC-1": `{"key": "EPIC-1", "fields": {"issuetype": {"name": "Epic"}, "status": {"name": "OPEN"}, "summary": "first epic"}}`,
+		"EPIC-2": `{"key": "EPIC-2", "fields": {"issuetype": {"name": "Epic"}, "status": {"name": "OPEN"}, "summary": "second epic"}}`,
+	}
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		queries = append(queries, jql)
+
+		var found []string
+		for _, k := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(jql, "issue in ("), ")"), ",") {
+			found = append(found, issues[k])
+		}
+		fmt.Fprintf(w, `{"names": {"customfield_10014": "Epic Link"}, "issues": [%s]}`, strings.Join(found, ","))
+	}))
+	defer srv.Close()
+
+	t.Run("parent field", func(t *testing.T) {
+		queries = nil
+		jira, err := issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword", issuetrackers.WithEpicRollUp(""))
+		assert.NoError(t, err, "NewJira error must be nil")
+
+		got, err := jira.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"AAA-1", "AAA-2", "AAA-3"})
+		assert.NoError(t, err, "GetIssues error must be nil")
+
+		assert.Equal(t, []string{"issue in (AAA-1,AAA-2,AAA-3)", "issue in (EPIC-1)"}, queries)
+		assert.Equal(t, "EPIC-1", got[0].Parent.IssueKey)
+		assert.Equal(t, "first epic", got[0].Parent.IssueSummary)
+		assert.Nil(t, got[1].Parent)
+		assert.Nil(t, got[2].Parent)
+	})
+
+	t.Run("epic link field", func(t *testing.T) {
+		queries = nil
+		jira, err := issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword", issuetrackers.WithEpicRollUp("Epic Link"))
+		assert.NoError(t, err, "NewJira error must be nil")
+
+		got, err := jira.GetIssues(context.Background(), &entities.EnrichedRepo{}, []string{"AAA-1", "AAA-2", "AAA-3"})
+		assert.NoError(t, err, "GetIssues error must be nil")
+
+		assert.Equal(t, []string{"issue in (AAA-1,AAA-2,AAA-3)", "issue in (EPIC-2)"}, queries)
+		assert.Nil(t, got[0].Parent)
+		assert.Equal(t, "second epic", got[1].Parent.IssueSummary)
+		assert.Nil(t, got[2].Parent)
+	})
+}
+
+func TestJiraGetKnownIssuesJQLTemplate(t *testing.T) {
+	var gotRequest *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequest = r
+		fmt.Fprint(w, `{"issues": []}`)
+	}))
+	defer srv.Close()
+
+	repo := &entities.EnrichedRepo{Repo: entities.Repo{
+		Label:            "service1",
+		FromTag:          "1.0.0",
+		ToTag:            "1.1.0",
+		Project:          "AAA",
+		Component:        "backend",
+		CustomAttributes: map[string]any{"line": "1.x"},
+	}}
+
+	tests := []struct {
+		name          string
+		defaultJQL    string
+		repoJQL       string
+		repoAttribute string
+		expectedQuery string
+		expectedError bool
+	}{
+		{
+			name:          "default template",
+			defaultJQL:    `affectedVersion = {{ quote .version }} and fixVersion != {{ quote .previousVersion }}`,
+			expectedQuery: `affectedVersion = "1.1.0" and fixVersion != "1.0.0" and project = "AAA" and component = "backend"`,
+		},
+		{
+			name:          "service template",
+			defaultJQL:    "status != Done",
+			repoJQL:       `labels = "{{ .label }}-{{ .customAttributes.line }}" and project = "{{ .jiraProject }}-{{ .jiraComponent }}"`,
+			expectedQuery: `labels = "service1-1.x" and project = "AAA-backend" and project = "AAA" and component = "backend"`,
+		},
+		{
+			name:          "quoted value",
+			defaultJQL:    `labels = {{ quote .customAttributes.label }}`,
+			repoAttribute: `release "1.x" \ hotfix`,
+			expectedQuery: `labels = "release \"1.x\" \\ hotfix" and project = "AAA" and component = "backend"`,
+		},
+		{
+			name:          "missing attribute",
+			defaultJQL:    `affectedVersion = "{{ .customAttributes.missing }}"`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRequest = nil
+			j, err := issuetrackers.NewJira(srv.URL, "jiraUsername", "jiraPassword", issuetrackers.WithKnownIssueJql(tt.defaultJQL))
+			assert.NoError(t, err, "NewJira error must be nil")
+
+			r := *repo
+			r.KnownIssuesJQL = tt.repoJQL
+			r.CustomAttributes = map[string]any{"line": "1.x", "label": tt.repoAttribute}
+			_, err = j.GetKnownIssues(context.Background(), &r)
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, gotRequest)
+				return
+			}
+			assert.NoError(t, err, "GetKnownIssues error must be nil")
+			assert.Equal(t, tt.expectedQuery, gotRequest.URL.Query().Get("jql"))
+		})
+	}
+}
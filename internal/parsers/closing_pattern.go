@@ -70,7 +70,7 @@ func (p ClosingPatternParser) Parse(s string) ([]CPIssue, error) {
 		keys := strings.TrimLeft(match, v+" ")
 		verb := extractVerb(v)
 
-		for _, key := range split(keys) {
+		for _, key := range SplitKeys(keys) {
 			category := entities.UNKNOWN
 			if verb == Close || verb == Implement {
 				category = entities.FEATURE
@@ -88,16 +88,19 @@ func (p ClosingPatternParser) Parse(s string) ([]CPIssue, error) {
 	return result, nil
 }
 
-func split(s string) []string {
+// keysSeparator separates the keys of a closing pattern, with "and" matched
+// as a whole word not to split the project paths including it.
+var keysSeparator = regexp.MustCompile(`,|\band\b`)
+
+// SplitKeys splits the list of keys separated by comma or "and".
+func SplitKeys(s string) []string {
 	result := make([]string, 0)
-	for _, v := range strings.Split(s, ",") {
-		for _, v2 := range strings.Split(v, "and") {
-			str := strings.TrimSpace(v2)
-			if str == "" {
-				continue
-			}
-			result = append(result, str)
+	for _, v := range keysSeparator.Split(s, -1) {
+		str := strings.TrimSpace(v)
+		if str == "" {
+			continue
 		}
+		result = append(result, str)
 	}
 	return result
 }
@@ -74,3 +74,33 @@ func TestParse(t *testing.T) {
 		}
 	}
 }
+
+func TestParseProjectReferences(t *testing.T) {
+	pcp := parsers.NewClosingPattern(
+		parsers.WithIssuePattern(`https?://[^/\s]+/(?P<project>[\w.-]+(?:/[\w.-]+)+?)(?:/-)?/(?:issues|pull)/(?P<key>\d+)`),
+		parsers.WithIssuePattern(`(?:(?P<project>[\w.-]+(?:/[\w.-]+)+))?#(?P<key>\d+)`))
+
+	got, err := pcp.Parse("Closes #1, platform/backend#2 and https://gitlab.example.com/platform/backlog/-/issues/3")
+	assert.NoError(t, err)
+
+	var keys []string
+	for _, i := range got {
+		keys = append(keys, i.Key)
+	}
+	assert.Equal(t, []string{"#1", "platform/backend#2", "https://gitlab.example.com/platform/backlog/-/issues/3"}, keys)
+}
+
+func TestParseIssueLinks(t *testing.T) {
+	pcp := parsers.NewClosingPattern(
+		parsers.WithIssuePattern(parsers.URLIssuePattern("jira", "https://jira.example.com/browse/{key}").Pattern),
+		parsers.WithIssuePattern(`[A-Z]+-\d+`))
+
+	got, err := pcp.Parse("Fixes https://jira.example.com/browse/ABC-12, ABC-13 and https://jira.example.com/browse/ABC-14")
+	assert.NoError(t, err)
+
+	var keys []string
+	for _, i := range got {
+		keys = append(keys, i.Key)
+	}
+	assert.Equal(t, []string{"https://jira.example.com/browse/ABC-12", "ABC-13", "https://jira.example.com/browse/ABC-14"}, keys)
+}
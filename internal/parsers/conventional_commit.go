@@ -8,8 +8,22 @@ import (
 )
 
 type CCParser struct {
-	re  regexp.Regexp
-	gni map[string]int
+	re         regexp.Regexp
+	gni        map[string]int
+	categories map[string]entities.CommitCategory
+}
+
+type CCParserOpt func(*CCParser)
+
+// WithTypeCategory sets the category of the commits of the type, case
+// insensitive. The configured types extend the default ones, feat for the
+// features and fix for the bug fixes, overriding them when set again.
+func WithTypeCategory(t string, category entities.CommitCategory) CCParserOpt {
+	return func(p *CCParser) {
+		if t != "" {
+			p.categories[strings.ToLower(t)] = category
+		}
+	}
 }
 
 type ConventionalCommit struct {
@@ -20,9 +34,13 @@ type ConventionalCommit struct {
 	Subject    string
 }
 
+// DefaultScopeSeparators are the characters separating the issue keys of a
+// scope referencing several issues, e.g. feat(ABC-1,ABC-2) or fix(ABC-1 #44).
+const DefaultScopeSeparators = ",; "
+
 const ccPattern = `^(?P<type>[^\(\:]*)(\((?P<scope>[^\)]+)\))?(?P<breaking>!)?: (?P<subject>.*)?`
 
-func NewConventionalCommit() CCParser {
+func NewConventionalCommit(opts ...CCParserOpt) CCParser {
 	re := regexp.MustCompile(ccPattern)
 	gn := re.SubexpNames()
 	gnidx := map[string]int{}
@@ -33,7 +51,15 @@ func NewConventionalCommit() CCParser {
 		}
 	}
 
-	return CCParser{re: *re, gni: gnidx}
+	p := CCParser{re: *re, gni: gnidx, categories: map[string]entities.CommitCategory{
+		"feat": entities.FEATURE,
+		"fix":  entities.BUG_FIX,
+	}}
+	for _, o := range opts {
+		o(&p)
+	}
+
+	return p
 }
 
 func (p CCParser) Parse(commit string) *ConventionalCommit {
@@ -43,25 +69,14 @@ func (p CCParser) Parse(commit string) *ConventionalCommit {
 		return nil
 	}
 
-	cct := entities.UNKNOWN
 	t := cc[0][p.gni["type"]]
-	if t == "feat" {
-		cct = entities.FEATURE
-	}
-
-	if t == "fix" {
-		cct = entities.BUG_FIX
-	}
+	cct := p.categories[strings.ToLower(t)]
 
 	isBreaking := false
 	if cc[0][p.gni["breaking"]] == "!" {
 		isBreaking = true
 	}
 
-	if strings.Contains(commit, "BREAKING CHANGE: ") {
-		isBreaking = true
-	}
-
 	return &ConventionalCommit{
 		Type:       t,
 		Category:   cct,
@@ -70,3 +85,10 @@ func (p CCParser) Parse(commit string) *ConventionalCommit {
 		IsBreaking: isBreaking,
 	}
 }
+
+// Scopes returns the parts of the scope split by any of the separators.
+func (c ConventionalCommit) Scopes(separators string) []string {
+	return strings.FieldsFunc(c.Scope, func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	})
+}
@@ -97,11 +97,11 @@ func TestConventionalCommit(t *testing.T) {
 		assert.Equal(t, true, cc.IsBreaking)
 	})
 
-	t.Run("parse is breaking change 2", func(t *testing.T) {
+	t.Run("parse breaking change footer only from the trailers", func(t *testing.T) {
 		parser := parsers.NewConventionalCommit()
-		cc := parser.Parse("feat(123): send an email to the customer when a product is shipped\n BREAKING CHANGE: the details")
+		cc := parser.Parse("feat(123): send an email to the customer when a product is shipped BREAKING CHANGE: the details")
 
-		assert.Equal(t, true, cc.IsBreaking)
+		assert.Equal(t, false, cc.IsBreaking)
 	})
 
 	t.Run("parse is not breaking change", func(t *testing.T) {
@@ -117,4 +117,40 @@ func TestConventionalCommit(t *testing.T) {
 
 		assert.Nil(t, cc)
 	})
+
+	t.Run("parse scope with several keys", func(t *testing.T) {
+		parser := parsers.NewConventionalCommit()
+		cc := parser.Parse("fix(ABC-1, ABC-2;#44): send an email to the customer when a product is shipped")
+
+		assert.Equal(t, "ABC-1, ABC-2;#44", cc.Scope)
+		assert.Equal(t, []string{"ABC-1", "ABC-2", "#44"}, cc.Scopes(parsers.DefaultScopeSeparators))
+		assert.Equal(t, []string{"ABC-1, ABC-2;#44"}, cc.Scopes(""))
+	})
+
+	t.Run("parse configured type", func(t *testing.T) {
+		parser := parsers.NewConventionalCommit(
+			parsers.WithTypeCategory("perf", entities.IMPROVEMENT),
+			parsers.WithTypeCategory("Security", entities.SECURITY))
+
+		assert.Equal(t, entities.IMPROVEMENT, parser.Parse("perf(ABC-1): faster search").Category)
+		assert.Equal(t, entities.SECURITY, parser.Parse("security(ABC-2): escape the input").Category)
+		assert.Equal(t, entities.FEATURE, parser.Parse("feat(ABC-3): new search").Category)
+		assert.Equal(t, entities.BUG_FIX, parser.Parse("fix(ABC-4): the search").Category)
+		assert.Equal(t, entities.UNKNOWN, parser.Parse("docs(ABC-5): the search").Category)
+	})
+
+	t.Run("parse overridden default type", func(t *testing.T) {
+		parser := parsers.NewConventionalCommit(parsers.WithTypeCategory("Fix", entities.SECURITY))
+
+		assert.Equal(t, entities.SECURITY, parser.Parse("fix(ABC-1): escape the input").Category)
+		assert.Equal(t, entities.FEATURE, parser.Parse("feat(ABC-2): new search").Category)
+	})
+
+	t.Run("parse default types", func(t *testing.T) {
+		parser := parsers.NewConventionalCommit()
+
+		assert.Equal(t, entities.FEATURE, parser.Parse("feat(ABC-1): new search").Category)
+		assert.Equal(t, entities.BUG_FIX, parser.Parse("fix(ABC-2): the search").Category)
+		assert.Equal(t, entities.UNKNOWN, parser.Parse("perf(ABC-3): faster search").Category)
+	})
 }
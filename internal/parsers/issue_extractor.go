@@ -3,25 +3,62 @@ package parsers
 import (
 	"regexp"
 	"strings"
+
+	"github.com/happyagosmith/jig/internal/entities"
 )
 
 const UNKNOWN_ISSUE_TRACKER = "NONE"
 
+// IssuePattern associates the issue keys matching the pattern to the issue
+// tracker. The key is the group named key or, if missing, the last group of
+// the pattern, while the group named project, if any, is the project of the issue.
 type IssuePattern struct {
 	IssueTracker string `yaml:"issueTracker" mapstructure:"issueTracker"`
 	Pattern      string `yaml:"pattern" mapstructure:"pattern"`
 }
 
+// URLIssuePattern returns the pattern of the links to the issues of the issue
+// tracker, built from the template of the issue web URL where {key} is replaced
+// by the issue key. The links match with both the http and https schemes.
+func URLIssuePattern(issueTracker, urlTemplate string) IssuePattern {
+	prefix, suffix, found := strings.Cut(urlTemplate, "{key}")
+	if !found {
+		prefix, suffix = urlTemplate, ""
+	}
+
+	prefix = regexp.QuoteMeta(prefix)
+	for _, scheme := range []string{"https://", "http://"} {
+		if p, ok := strings.CutPrefix(prefix, scheme); ok {
+			prefix = `https?://` + p
+			break
+		}
+	}
+
+	return IssuePattern{
+		IssueTracker: issueTracker,
+		Pattern:      prefix + `(?P<key>[^/\s?#,)\]>]+)` + regexp.QuoteMeta(suffix),
+	}
+}
+
 type IssueExtractor struct {
 	re  []regexp.Regexp
 	ips []IssuePattern
 }
 
+// IssueDetail is the issue referenced by a commit. Project is set when the
+// issue belongs to another project than the one of the repo, e.g. with the
+// references group/project#12.
 type IssueDetail struct {
 	Key          string
+	Project      string
 	IssueTracker string
 }
 
+// Ref returns the key of the issue including its project, if any.
+func (d IssueDetail) Ref() string {
+	return entities.IssueRef(d.Project, d.Key)
+}
+
 type IssueExtractorOpt func(*IssueExtractor)
 
 func WithIssueTracker(it IssuePattern) IssueExtractorOpt {
@@ -55,12 +92,25 @@ func (p IssueExtractor) Parse(sToParse string) *IssueDetail {
 			continue
 		}
 
-		idx := len(matches[0]) - 1
-		key := matches[0][idx]
+		key := namedGroup(re, matches[0], "key")
+		if key == "" {
+			key = matches[0][len(matches[0])-1]
+		}
 		if key != "" {
-			return &IssueDetail{Key: key, IssueTracker: p.ips[i].IssueTracker}
+			return &IssueDetail{Key: key, Project: namedGroup(re, matches[0], "project"), IssueTracker: p.ips[i].IssueTracker}
 		}
 	}
 
 	return &IssueDetail{Key: sToParse, IssueTracker: UNKNOWN_ISSUE_TRACKER}
 }
+
+// namedGroup returns the first not empty value of the groups with the name.
+func namedGroup(re regexp.Regexp, match []string, name string) string {
+	for i, n := range re.SubexpNames() {
+		if n == name && match[i] != "" {
+			return match[i]
+		}
+	}
+
+	return ""
+}
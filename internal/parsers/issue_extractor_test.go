@@ -51,3 +51,91 @@ func TestITParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestITParser_ParseProjectReferences(t *testing.T) {
+	parser := parsers.NewIssueExtractor(
+		parsers.WithIssueTracker(
+			parsers.IssuePattern{IssueTracker: "git", Pattern: `https?://[^/\s]+/(?P<project>[\w.-]+(?:/[\w.-]+)+?)(?:/-)?/(?:issues|pull)/(?P<key>\d+)`}),
+		parsers.WithIssueTracker(
+			parsers.IssuePattern{IssueTracker: "git", Pattern: `(?:(?P<project>[\w.-]+(?:/[\w.-]+)+))?#(?P<key>\d+)`}))
+
+	tests := []struct {
+		sToParse string
+		want     *parsers.IssueDetail
+		wantRef  string
+	}{
+		{
+			sToParse: "#12",
+			want:     &parsers.IssueDetail{Key: "12", IssueTracker: "GIT"},
+			wantRef:  "12",
+		},
+		{
+			sToParse: "platform/backlog#12",
+			want:     &parsers.IssueDetail{Key: "12", Project: "platform/backlog", IssueTracker: "GIT"},
+			wantRef:  "platform/backlog#12",
+		},
+		{
+			sToParse: "https://gitlab.example.com/platform/sub/backlog/-/issues/12",
+			want:     &parsers.IssueDetail{Key: "12", Project: "platform/sub/backlog", IssueTracker: "GIT"},
+			wantRef:  "platform/sub/backlog#12",
+		},
+		{
+			sToParse: "https://github.com/owner/repo/issues/7",
+			want:     &parsers.IssueDetail{Key: "7", Project: "owner/repo", IssueTracker: "GIT"},
+			wantRef:  "owner/repo#7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sToParse, func(t *testing.T) {
+			got := parser.Parse(tt.sToParse)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ITParser.Parse() = %v, want %v", got, tt.want)
+			}
+			if got.Ref() != tt.wantRef {
+				t.Errorf("IssueDetail.Ref() = %v, want %v", got.Ref(), tt.wantRef)
+			}
+		})
+	}
+}
+
+func TestURLIssuePattern(t *testing.T) {
+	parser := parsers.NewIssueExtractor(
+		parsers.WithIssueTracker(parsers.URLIssuePattern("jira", "https://jira.example.com/browse/{key}")),
+		parsers.WithIssueTracker(parsers.URLIssuePattern("silk", "https://silk.example.com/issues/{key}/view")),
+		parsers.WithIssueTracker(parsers.IssuePattern{IssueTracker: "jira", Pattern: `[A-Z]+-\d+`}))
+
+	tests := []struct {
+		sToParse string
+		want     *parsers.IssueDetail
+	}{
+		{
+			sToParse: "https://jira.example.com/browse/ABC-12",
+			want:     &parsers.IssueDetail{Key: "ABC-12", IssueTracker: "JIRA"},
+		},
+		{
+			sToParse: "http://jira.example.com/browse/ABC-12?focusedCommentId=1",
+			want:     &parsers.IssueDetail{Key: "ABC-12", IssueTracker: "JIRA"},
+		},
+		{
+			sToParse: "https://silk.example.com/issues/42/view",
+			want:     &parsers.IssueDetail{Key: "42", IssueTracker: "SILK"},
+		},
+		{
+			sToParse: "https://jiraXexample.com/browse/ABC-12",
+			want:     &parsers.IssueDetail{Key: "ABC-12", IssueTracker: "JIRA"},
+		},
+		{
+			sToParse: "https://other.example.com/issues/42/view",
+			want:     &parsers.IssueDetail{Key: "https://other.example.com/issues/42/view", IssueTracker: "NONE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sToParse, func(t *testing.T) {
+			if got := parser.Parse(tt.sToParse); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ITParser.Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
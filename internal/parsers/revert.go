@@ -0,0 +1,38 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Revert is a commit reverting another one, like the ones created by git revert.
+type Revert struct {
+	// Title is the title of the reverted commit.
+	Title string
+	// CommitID is the id of the reverted commit, empty when not referenced.
+	CommitID string
+}
+
+var (
+	revertTitleRe  = regexp.MustCompile(`^Revert "(.+)"\s*$`)
+	revertCommitRe = regexp.MustCompile(`(?m)This reverts commit ([0-9a-fA-F]{7,40})`)
+)
+
+// ParseRevert returns the commit reverted by the one with the title and the
+// message, nil if it is not a revert. The revert is recognised by the title
+// Revert "<title of the reverted commit>" or by the line "This reverts commit
+// <sha>" of the message.
+func ParseRevert(title, message string) *Revert {
+	var r Revert
+	if m := revertTitleRe.FindStringSubmatch(strings.TrimSpace(title)); m != nil {
+		r.Title = m[1]
+	}
+	if m := revertCommitRe.FindStringSubmatch(message); m != nil {
+		r.CommitID = strings.ToLower(m[1])
+	}
+	if r.Title == "" && r.CommitID == "" {
+		return nil
+	}
+
+	return &r
+}
@@ -0,0 +1,48 @@
+package parsers_test
+
+import (
+	"testing"
+
+	"github.com/happyagosmith/jig/internal/parsers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRevert(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		message string
+		want    *parsers.Revert
+	}{
+		{
+			name:    "git revert",
+			title:   `Revert "feat(ABC-1): new endpoint"`,
+			message: "Revert \"feat(ABC-1): new endpoint\"\n\nThis reverts commit 8A3F5C2D1E0B9A8F7E6D5C4B3A2F1E0D9C8B7A6F.",
+			want:    &parsers.Revert{Title: "feat(ABC-1): new endpoint", CommitID: "8a3f5c2d1e0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f"},
+		},
+		{
+			name:    "revert merge request",
+			title:   `Revert "feat(ABC-1): new endpoint"`,
+			message: "This reverts merge request !12",
+			want:    &parsers.Revert{Title: "feat(ABC-1): new endpoint"},
+		},
+		{
+			name:    "short id",
+			title:   "fix: restore the endpoint",
+			message: "fix: restore the endpoint\n\nThis reverts commit 8a3f5c2.",
+			want:    &parsers.Revert{CommitID: "8a3f5c2"},
+		},
+		{
+			name:    "not a revert",
+			title:   "feat(ABC-1): revert the order",
+			message: "feat(ABC-1): revert the order",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parsers.ParseRevert(tt.title, tt.message))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Trailer is a footer of the conventional commits, or a git trailer, like
+// "Refs: ABC-123", "Refs #12" or "BREAKING CHANGE: the description".
+type Trailer struct {
+	Token string
+	Value string
+}
+
+// IsBreakingChange reports whether the trailer describes a breaking change.
+func (t Trailer) IsBreakingChange() bool {
+	return t.Token == "BREAKING CHANGE" || t.Token == "BREAKING-CHANGE"
+}
+
+var (
+	trailerRe   = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][\w-]*)(?::(?:[ \t]+(.*))?| (#.*))$`)
+	paragraphRe = regexp.MustCompile(`\n[ \t]*\n`)
+)
+
+// ParseTrailers returns the trailers of the commit message, that are the last
+// paragraphs of the message, after the one of the title, starting with a
+// trailer. The lines not starting with a trailer continue the value of the
+// previous one, so the description of a breaking change can span several lines.
+func ParseTrailers(message string) []Trailer {
+	paragraphs := paragraphRe.Split(strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n")), -1)
+
+	var trailers []Trailer
+	for i := len(paragraphs) - 1; i > 0; i-- {
+		pt, ok := parseTrailersParagraph(paragraphs[i])
+		if !ok {
+			break
+		}
+		trailers = append(pt, trailers...)
+	}
+
+	return trailers
+}
+
+func parseTrailersParagraph(paragraph string) ([]Trailer, bool) {
+	var trailers []Trailer
+	for _, line := range strings.Split(paragraph, "\n") {
+		m := trailerRe.FindStringSubmatch(strings.TrimRight(line, " \t"))
+		if m != nil {
+			trailers = append(trailers, Trailer{Token: m[1], Value: m[2] + m[3]})
+			continue
+		}
+		if len(trailers) == 0 {
+			return nil, false
+		}
+		last := &trailers[len(trailers)-1]
+		last.Value = strings.TrimLeft(last.Value+"\n"+strings.TrimSpace(line), "\n")
+	}
+
+	return trailers, len(trailers) > 0
+}
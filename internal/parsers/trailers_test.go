@@ -0,0 +1,59 @@
+package parsers_test
+
+import (
+	"testing"
+
+	"github.com/happyagosmith/jig/internal/parsers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrailers(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []parsers.Trailer
+	}{
+		{
+			name: "footers",
+			message: "feat(api): new endpoint\n\nThe body of the commit.\n\n" +
+				"BREAKING CHANGE: the old endpoint is removed,\n  use the new one\n" +
+				"Refs: ABC-1, ABC-2\nRefs #12\nCo-authored-by: Jane Doe <jane@example.com>\nRelease-Note: a new endpoint\n",
+			want: []parsers.Trailer{
+				{Token: "BREAKING CHANGE", Value: "the old endpoint is removed,\nuse the new one"},
+				{Token: "Refs", Value: "ABC-1, ABC-2"},
+				{Token: "Refs", Value: "#12"},
+				{Token: "Co-authored-by", Value: "Jane Doe <jane@example.com>"},
+				{Token: "Release-Note", Value: "a new endpoint"},
+			},
+		},
+		{
+			name:    "footers in several paragraphs",
+			message: "fix: a bug\n\nBREAKING-CHANGE: the behaviour changed\n\nJira: ABC-3",
+			want: []parsers.Trailer{
+				{Token: "BREAKING-CHANGE", Value: "the behaviour changed"},
+				{Token: "Jira", Value: "ABC-3"},
+			},
+		},
+		{
+			name:    "body is not a footer",
+			message: "fix: a bug\n\nsee https://example.com/doc for the details",
+			want:    nil,
+		},
+		{
+			name:    "title is not a footer",
+			message: "fix: a bug\nRefs: ABC-1",
+			want:    nil,
+		},
+		{
+			name:    "body before the footers",
+			message: "fix: a bug\n\nNote: the body\nof the commit\n\nthe end of the body\n\nRefs: ABC-1",
+			want:    []parsers.Trailer{{Token: "Refs", Value: "ABC-1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parsers.ParseTrailers(tt.message))
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/happyagosmith/jig/internal/entities"
+)
+
+const gitHubPublicURL = "https://github.com"
+
+type GitHub struct {
+	c               *github.Client
+	repository      string
+	labelCategories []labelCategory
+	typeCategories  []labelCategory
+	defaultCategory entities.IssueCategory
+	knownIssues     entities.KnownIssuesFilter
+}
+
+type GitHubOpt func(*GitHub)
+
+// WithGitHubRepository sets the repository owner/repo of the issues, instead
+// of the repository of each service, to use GitHub as issue tracker of services
+// hosted elsewhere.
+func WithGitHubRepository(ownerRepo string) GitHubOpt {
+	return func(g *GitHub) {
+		g.repository = ownerRepo
+	}
+}
+
+// WithGitHubLabelCategory associates the issues having the label to the
+// category. The labels are evaluated in the order they are added.
+func WithGitHubLabelCategory(label string, category entities.IssueCategory) GitHubOpt {
+	return func(g *GitHub) {
+		g.labelCategories = append(g.labelCategories, labelCategory{label: strings.TrimSpace(label), category: category})
+	}
+}
+
+// WithIssueTypeCategory associates the issues of the GitHub issue type to the
+// category. The issue types take precedence over the labels.
+func WithIssueTypeCategory(issueType string, category entities.IssueCategory) GitHubOpt {
+	return func(g *GitHub) {
+		g.typeCategories = append(g.typeCategories, labelCategory{label: strings.TrimSpace(issueType), category: category})
+	}
+}
+
+// WithGitHubDefaultCategory sets the category of the issues not matching any
+// issue type or label.
+func WithGitHubDefaultCategory(category entities.IssueCategory) GitHubOpt {
+	return func(g *GitHub) {
+		g.defaultCategory = category
+	}
+}
+
+// WithGitHubKnownIssues sets the default filter of the open issues retrieved as
+// known issues, the issues labelled bug when the filter is empty.
+func WithGitHubKnownIssues(f entities.KnownIssuesFilter) GitHubOpt {
+	return func(g *GitHub) {
+		g.knownIssues = f
+	}
+}
+
+// NewGitHub creates a GitHub client. When URL is empty or points to github.com
+// the public API is used, otherwise URL is considered a GitHub Enterprise instance.
+func NewGitHub(URL, token string, opts ...GitHubOpt) (GitHub, error) {
+	c := github.NewClient(nil)
+	if token != "" {
+		c = c.WithAuthToken(token)
+	}
+
+	URL = strings.TrimSuffix(URL, "/")
+	if URL != "" && URL != gitHubPublicURL {
+		var err error
+		c, err = c.WithEnterpriseURLs(URL, URL)
+		if err != nil {
+			return GitHub{}, fmt.Errorf("failed to create client: %w", err)
+		}
+	}
+
+	g := GitHub{
+		c:               c,
+		defaultCategory: entities.CLOSED_FEATURE,
+	}
+	for _, o := range opts {
+		o(&g)
+	}
+	if len(g.labelCategories) == 0 {
+		g.labelCategories = []labelCategory{
+			{label: "feature", category: entities.CLOSED_FEATURE},
+			{label: "enhancement", category: entities.CLOSED_FEATURE},
+			{label: "bug", category: entities.FIXED_BUG},
+		}
+	}
+	if g.knownIssues.IsEmpty() {
+		g.knownIssues = entities.KnownIssuesFilter{Labels: []string{"bug"}}
+	}
+
+	return g, nil
+}
+
+func splitRepoID(id string) (string, string, error) {
+	owner, repo, ok := strings.Cut(id, "/")
+	if !ok || owner == "" || repo == "" {
+		return "", "", fmt.Errorf("invalid GitHub repository id %q, expected owner/repo", id)
+	}
+
+	return owner, repo, nil
+}
+
+func (g GitHub) GetMergeRequests(id, targetBranch string, commits []entities.RepoRecord) ([]entities.RepoRecord, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	owner, repo, err := splitRepoID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lookForCommit := map[string]bool{}
+	for _, c := range commits {
+		lookForCommit[c.ID] = true
+	}
+	updatedAfter := commits[0].CreatedAt
+
+	opts := &github.PullRequestListOptions{
+		State:       "closed",
+		Base:        targetBranch,
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var cs []entities.RepoRecord
+	for {
+		prs, resp, err := g.c.PullRequests.List(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		stop := false
+		for _, pr := range prs {
+			if updatedAfter != nil && pr.UpdatedAt != nil && pr.UpdatedAt.Before(*updatedAfter) {
+				stop = true
+				break
+			}
+			if pr.MergedAt == nil {
+				continue
+			}
+			commitIDs, err := g.mergedCommits(owner, repo, pr, lookForCommit)
+			if err != nil {
+				return nil, err
+			}
+			if len(commitIDs) == 0 {
+				continue
+			}
+			cs = append(cs, entities.RepoRecord{
+				ID:             strconv.FormatInt(pr.GetID(), 10),
+				ShortID:        strconv.Itoa(pr.GetNumber()),
+				Title:          pr.GetTitle(),
+				Message:        pr.GetBody(),
+				CreatedAt:      pr.MergedAt.GetTime(),
+				WebURL:         pr.GetHTMLURL(),
+				Origin:         "merge_request",
+				CommitIDs:      commitIDs,
+				MergeCommitIDs: []string{pr.GetMergeCommitSHA()},
+			})
+		}
+
+		if stop || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return cs, nil
+}
+
+// mergedCommits returns the ids of the commits in lookForCommit merged by the
+// pull request. The pull request is matched through its merge commit and, when
+// the merge commit is not in lookForCommit, e.g. for the pull requests merged
+// by rebase, through its commits.
+func (g GitHub) mergedCommits(owner, repo string, pr *github.PullRequest, lookForCommit map[string]bool) ([]string, error) {
+	if sha := pr.GetMergeCommitSHA(); sha != "" && lookForCommit[sha] {
+		return []string{sha}, nil
+	}
+
+	var commitIDs []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		prCommits, resp, err := g.c.PullRequests.ListCommits(context.Background(), owner, repo, pr.GetNumber(), opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range prCommits {
+			if lookForCommit[c.GetSHA()] && !slices.Contains(commitIDs, c.GetSHA()) {
+				commitIDs = append(commitIDs, c.GetSHA())
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return commitIDs, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (g GitHub) GetCommits(id, from, to string) ([]entities.RepoRecord, error) {
+	owner, repo, err := splitRepoID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var commits []entities.RepoRecord
+	for {
+		c, resp, err := g.c.Repositories.CompareCommits(context.Background(), owner, repo, from, to, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, commit := range c.Commits {
+			sha := commit.GetSHA()
+			message := commit.GetCommit().GetMessage()
+			commits = append(commits, entities.RepoRecord{
+				ID:        sha,
+				ShortID:   shortSHA(sha),
+				Title:     strings.Split(message, "\n")[0],
+				Message:   message,
+				CreatedAt: commitDate(commit),
+				WebURL:    commit.GetHTMLURL(),
+				Origin:    "commit",
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return commits, nil
+}
+
+func commitDate(commit *github.RepositoryCommit) *time.Time {
+	committer := commit.GetCommit().GetCommitter()
+	if committer == nil {
+		return nil
+	}
+
+	return committer.Date.GetTime()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+
+	return sha
+}
+
+func (g GitHub) GetRepoURL(gitRepoID string) (string, error) {
+	owner, repo, err := splitRepoID(gitRepoID)
+	if err != nil {
+		return "", err
+	}
+
+	r, _, err := g.c.Repositories.Get(context.Background(), owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	return r.GetHTMLURL(), nil
+}
+
+func (g GitHub) GetReleaseURL(gitRepoID, version string) (string, error) {
+	owner, repo, err := splitRepoID(gitRepoID)
+	if err != nil {
+		return "", err
+	}
+
+	r, _, err := g.c.Repositories.GetReleaseByTag(context.Background(), owner, repo, version)
+	if err != nil {
+		return "", err
+	}
+
+	return r.GetHTMLURL(), nil
+}
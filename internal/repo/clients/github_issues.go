@@ -0,0 +1,280 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/happyagosmith/jig/internal/entities"
+)
+
+// gitHubIssuesBatchSize is the max number of issues retrieved with each GraphQL query.
+const gitHubIssuesBatchSize = 50
+
+// gitHubIssue is an issue or a pull request returned by the GraphQL API.
+type gitHubIssue struct {
+	Typename string `json:"__typename"`
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	URL      string `json:"url"`
+	Labels   struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	IssueType *struct {
+		Name string `json:"name"`
+	} `json:"issueType"`
+}
+
+type gitHubIssuesResponse struct {
+	Data struct {
+		Repository map[string]*gitHubIssue `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLURL returns the GraphQL endpoint of github.com or of the GitHub Enterprise instance.
+func (g GitHub) graphQLURL() string {
+	u := *g.c.BaseURL
+	if p, ok := strings.CutSuffix(u.Path, "/api/v3/"); ok {
+		u.Path = p + "/api/graphql"
+	} else {
+		u.Path += "graphql"
+	}
+
+	return u.String()
+}
+
+func (g GitHub) repositoryOf(repo *entities.EnrichedRepo) (string, string, error) {
+	if g.repository != "" {
+		return splitRepoID(g.repository)
+	}
+
+	return splitRepoID(repo.ID)
+}
+
+// issuesQuery returns the GraphQL query retrieving the issues or pull requests
+// with the numbers, each one with the alias i<number>.
+func (g GitHub) issuesQuery(owner, name string, numbers []int) string {
+	fields := "number title state url labels(first: 100) { nodes { name } }"
+	issueFields := fields
+	if len(g.typeCategories) > 0 {
+		issueFields += " issueType { name }"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "query { repository(owner: %s, name: %s) {", strconv.Quote(owner), strconv.Quote(name))
+	for _, n := range numbers {
+		fmt.Fprintf(&b, " i%d: issueOrPullRequest(number: %d) { __typename ... on Issue { %s } ... on PullRequest { %s } }", n, n, issueFields, fields)
+	}
+	b.WriteString(" } }")
+
+	return b.String()
+}
+
+// GetIssues retrieves the issues and the pull requests with the numbers ids in
+// batches, using the GraphQL API, from the repository of the repo or, for the
+// keys owner/repo#number, from the referenced repository. The numbers not found are skipped.
+func (g GitHub) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, ids []string) ([]entities.Issue, error) {
+	var projects []string
+	numbers := map[string][]int{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		project, key := entities.SplitIssueRef(id)
+		num, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, err
+		}
+		ref := entities.IssueRef(project, strconv.Itoa(num))
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		if _, ok := numbers[project]; !ok {
+			projects = append(projects, project)
+		}
+		numbers[project] = append(numbers[project], num)
+	}
+
+	var issueDetails []entities.Issue
+	for _, project := range projects {
+		owner, name, err := g.repositoryOf(repo)
+		if project != "" {
+			owner, name, err = splitRepoID(project)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		issues, err := g.getIssues(ctx, owner, name, numbers[project])
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			issue.IssueKey = entities.IssueRef(project, issue.IssueKey)
+			issueDetails = append(issueDetails, issue)
+		}
+	}
+
+	return issueDetails, nil
+}
+
+func (g GitHub) getIssues(ctx context.Context, owner, name string, numbers []int) ([]entities.Issue, error) {
+	var issueDetails []entities.Issue
+	for len(numbers) > 0 {
+		batch := numbers[:min(gitHubIssuesBatchSize, len(numbers))]
+		numbers = numbers[len(batch):]
+
+		req, err := g.c.NewRequest(http.MethodPost, g.graphQLURL(), map[string]string{"query": g.issuesQuery(owner, name, batch)})
+		if err != nil {
+			return nil, err
+		}
+		var resp gitHubIssuesResponse
+		if _, err := g.c.Do(ctx, req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to retrieve the issues of %s/%s: %w", owner, name, err)
+		}
+		for _, e := range resp.Errors {
+			if e.Type != "NOT_FOUND" {
+				return nil, fmt.Errorf("failed to retrieve the issues of %s/%s: %s", owner, name, e.Message)
+			}
+			fmt.Printf("%s\n", e.Message)
+		}
+
+		for _, n := range batch {
+			if issue := resp.Data.Repository[fmt.Sprintf("i%d", n)]; issue != nil {
+				issueDetails = append(issueDetails, g.toGraphQLIssue(issue))
+			}
+		}
+	}
+
+	return issueDetails, nil
+}
+
+// toGraphQLIssue maps the issue, with the GitHub issue type as type when set,
+// "issue" otherwise, and "pull_request" for the pull requests.
+func (g GitHub) toGraphQLIssue(issue *gitHubIssue) entities.Issue {
+	issueType, typeName := "issue", ""
+	if issue.Typename == "PullRequest" {
+		issueType = "pull_request"
+	} else if issue.IssueType != nil && issue.IssueType.Name != "" {
+		issueType, typeName = issue.IssueType.Name, issue.IssueType.Name
+	}
+
+	var labels []string
+	for _, l := range issue.Labels.Nodes {
+		labels = append(labels, l.Name)
+	}
+
+	return entities.Issue{
+		IssueKey:     strconv.Itoa(issue.Number),
+		IssueSummary: issue.Title,
+		IssueStatus:  strings.ToLower(issue.State),
+		IssueType:    issueType,
+		Category:     g.extractIssueCategory(typeName, labels),
+		WebURL:       issue.URL,
+		Labels:       labels,
+	}
+}
+
+func (g GitHub) toIssue(issue *github.Issue) entities.Issue {
+	issueType := "issue"
+	if issue.IsPullRequest() {
+		issueType = "pull_request"
+	}
+
+	var labels []string
+	for _, l := range issue.Labels {
+		labels = append(labels, l.GetName())
+	}
+
+	return entities.Issue{
+		IssueKey:     strconv.Itoa(issue.GetNumber()),
+		IssueSummary: issue.GetTitle(),
+		IssueStatus:  issue.GetState(),
+		IssueType:    issueType,
+		Category:     g.extractIssueCategory("", labels),
+		WebURL:       issue.GetHTMLURL(),
+		Labels:       labels,
+	}
+}
+
+// extractIssueCategory returns the category of the issue type, if any,
+// otherwise the category of the first label rule matching a label of the issue.
+func (g GitHub) extractIssueCategory(issueType string, labels []string) entities.IssueCategory {
+	for _, tc := range g.typeCategories {
+		if issueType != "" && tc.match(issueType) {
+			return tc.category
+		}
+	}
+
+	for _, lc := range g.labelCategories {
+		for _, label := range labels {
+			if lc.match(label) {
+				return lc.category
+			}
+		}
+	}
+
+	return g.defaultCategory
+}
+
+// knownIssuesQuery returns the search query of the open issues matching the filter.
+func knownIssuesQuery(owner, name string, f entities.KnownIssuesFilter) string {
+	q := []string{fmt.Sprintf("repo:%s/%s", owner, name), "is:issue", "is:open"}
+	for _, l := range f.Labels {
+		q = append(q, fmt.Sprintf("label:%s", strconv.Quote(l)))
+	}
+	if f.Milestone != "" {
+		q = append(q, fmt.Sprintf("milestone:%s", strconv.Quote(f.Milestone)))
+	}
+	if f.IssueType != "" {
+		q = append(q, fmt.Sprintf("type:%s", strconv.Quote(f.IssueType)))
+	}
+
+	return strings.Join(q, " ")
+}
+
+// GetKnownIssues searches the open issues of the repo matching the known issues
+// filter. Nothing is retrieved when no filter criteria is configured.
+func (g GitHub) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
+	f := repo.KnownIssuesFilter.WithDefaults(g.knownIssues)
+	if f.IsEmpty() {
+		return nil, nil
+	}
+
+	owner, name, err := g.repositoryOf(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	query := knownIssuesQuery(owner, name, f)
+	fmt.Printf("\nretrieving known issues using GitHub search %s\n", query)
+
+	opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var issueDetails []entities.Issue
+	for {
+		result, resp, err := g.c.Search.Issues(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search known issues: %w", err)
+		}
+
+		for _, issue := range result.Issues {
+			issueDetails = append(issueDetails, g.toIssue(issue))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return issueDetails, nil
+}
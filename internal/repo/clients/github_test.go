@@ -0,0 +1,383 @@
+package clients_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/happyagosmith/jig/internal/entities"
+	"github.com/happyagosmith/jig/internal/repo/clients"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubGetRepoURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v3/repos/my/repo" {
+			rw.Write([]byte(`{"html_url": "https://github.example.com/my/repo"}`))
+		} else {
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g, err := clients.NewGitHub(server.URL, "token")
+	assert.NoError(t, err)
+	repoURL, err := g.GetRepoURL("my/repo")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/my/repo", repoURL)
+}
+
+func TestGitHubInvalidRepoID(t *testing.T) {
+	g, err := clients.NewGitHub("", "token")
+	assert.NoError(t, err)
+
+	_, err = g.GetRepoURL("123")
+	assert.Error(t, err)
+}
+
+func TestGitHubGetReleaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v3/repos/my/repo/releases/tags/v1.0.0" {
+			rw.Write([]byte(`{"html_url": "https://github.example.com/my/repo/releases/tag/v1.0.0"}`))
+		} else {
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g, err := clients.NewGitHub(server.URL, "token")
+	assert.NoError(t, err)
+	releaseURL, err := g.GetReleaseURL("my/repo", "v1.0.0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/my/repo/releases/tag/v1.0.0", releaseURL)
+}
+
+func TestGitHubGetCommits(t *testing.T) {
+	var gotAuth string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v3/repos/my/repo/compare/0.0.0...0.0.1" {
+			gotAuth = req.Header.Get("Authorization")
+			rw.Write([]byte(`{
+				"commits": [
+					{
+						"sha": "0123456789abcdef",
+						"html_url": "https://github.example.com/my/repo/commit/0123456789abcdef",
+						"commit": {
+							"message": "Test commit\n\nThis is a test commit",
+							"committer": {"date": "2021-01-01T00:00:00Z"}
+						}
+					}
+				]
+			}`))
+		} else {
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	commits, err := g.GetCommits("my/repo", "0.0.0", "0.0.1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(commits) != 1 {
+		t.Fatalf("Expected %d commits, got %d", 1, len(commits))
+	}
+
+	wantCreatedAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Bearer token", gotAuth)
+	assert.Equal(t, "0123456789abcdef", commits[0].ID)
+	assert.Equal(t, "0123456", commits[0].ShortID)
+	assert.Equal(t, "Test commit", commits[0].Title)
+	assert.Equal(t, "Test commit\n\nThis is a test commit", commits[0].Message)
+	assert.Equal(t, wantCreatedAt, *commits[0].CreatedAt)
+	assert.Equal(t, "https://github.example.com/my/repo/commit/0123456789abcdef", commits[0].WebURL)
+	assert.Equal(t, "commit", commits[0].Origin)
+}
+
+func TestGitHubGetMergeRequests(t *testing.T) {
+	gotParams := url.Values{}
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v3/repos/my/repo/pulls" {
+			gotParams = req.URL.Query()
+			rw.Write([]byte(`[
+					{
+						"id": 10,
+						"number": 1,
+						"title": "this is a pull request",
+						"body": "this is a pull request description",
+						"merge_commit_sha": "commit1",
+						"html_url": "https://github.example.com/my/repo/pull/1",
+						"merged_at": "2021-01-02T00:00:00Z",
+						"updated_at": "2021-01-02T00:00:00Z"
+					},
+					{
+						"id": 11,
+						"number": 2,
+						"title": "this is a closed pull request",
+						"merge_commit_sha": "commit1",
+						"html_url": "https://github.example.com/my/repo/pull/2",
+						"updated_at": "2021-01-02T00:00:00Z"
+					},
+					{
+						"id": 12,
+						"number": 3,
+						"title": "this is a pull request of another release",
+						"merge_commit_sha": "commit2",
+						"html_url": "https://github.example.com/my/repo/pull/3",
+						"merged_at": "2021-01-02T00:00:00Z",
+						"updated_at": "2021-01-02T00:00:00Z"
+					}
+				]`))
+		} else if req.URL.Path == "/api/v3/repos/my/repo/pulls/3/commits" {
+			rw.Write([]byte(`[{"sha": "commit2"}]`))
+		} else {
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits := []entities.RepoRecord{{ID: "commit1", CreatedAt: &createdAt}}
+
+	mrs, err := g.GetMergeRequests("my/repo", "main", commits)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, "main", gotParams.Get("base"))
+	assert.Equal(t, "closed", gotParams.Get("state"))
+
+	if len(mrs) != 1 {
+		t.Fatalf("Expected %d merge requests, got %d", 1, len(mrs))
+	}
+
+	wantMergedAt := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "10", mrs[0].ID)
+	assert.Equal(t, "1", mrs[0].ShortID)
+	assert.Equal(t, "this is a pull request", mrs[0].Title)
+	assert.Equal(t, "this is a pull request description", mrs[0].Message)
+	assert.Equal(t, wantMergedAt, *mrs[0].CreatedAt)
+	assert.Equal(t, "https://github.example.com/my/repo/pull/1", mrs[0].WebURL)
+	assert.Equal(t, "merge_request", mrs[0].Origin)
+	assert.Equal(t, []string{"commit1"}, mrs[0].CommitIDs)
+	assert.Equal(t, []string{"commit1"}, mrs[0].MergeCommitIDs)
+}
+
+func TestGitHubGetMergeRequestsRebased(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v3/repos/my/repo/pulls":
+			rw.Write([]byte(`[
+				{"id": 10, "number": 1, "title": "merged", "merge_commit_sha": "merge1", "merged_at": "2021-01-02T00:00:00Z", "updated_at": "2021-01-02T00:00:00Z"},
+				{"id": 11, "number": 2, "title": "rebased", "merge_commit_sha": "rebased2", "merged_at": "2021-01-02T00:00:00Z", "updated_at": "2021-01-02T00:00:00Z"},
+				{"id": 12, "number": 3, "title": "another release", "merge_commit_sha": "merge3", "merged_at": "2021-01-02T00:00:00Z", "updated_at": "2021-01-02T00:00:00Z"}
+			]`))
+		case "/api/v3/repos/my/repo/pulls/1/commits":
+			t.Errorf("unexpected request of the commits of the pull request with the merge commit in the range")
+		case "/api/v3/repos/my/repo/pulls/2/commits":
+			if req.URL.Query().Get("page") == "2" {
+				rw.Write([]byte(`[{"sha": "commit3"}]`))
+				return
+			}
+			rw.Header().Set("Link", `<`+"http://"+req.Host+req.URL.Path+`?page=2>; rel="next"`)
+			rw.Write([]byte(`[{"sha": "commit2"}, {"sha": "unknown"}]`))
+		case "/api/v3/repos/my/repo/pulls/3/commits":
+			rw.Write([]byte(`[{"sha": "unknown"}]`))
+		default:
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	commits := []entities.RepoRecord{{ID: "merge1", CreatedAt: &createdAt}, {ID: "commit2"}, {ID: "commit3"}}
+	mrs, err := g.GetMergeRequests("my/repo", "main", commits)
+	assert.NoError(t, err)
+
+	if len(mrs) != 2 {
+		t.Fatalf("Expected %d merge requests, got %d", 2, len(mrs))
+	}
+	assert.Equal(t, "1", mrs[0].ShortID)
+	assert.Equal(t, []string{"merge1"}, mrs[0].CommitIDs)
+	assert.Equal(t, "2", mrs[1].ShortID)
+	assert.Equal(t, []string{"commit2", "commit3"}, mrs[1].CommitIDs)
+	assert.Equal(t, []string{"rebased2"}, mrs[1].MergeCommitIDs)
+}
+
+func TestGitHubGetIssues(t *testing.T) {
+	var gotQueries []string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/graphql" {
+			http.Error(rw, "Not found", http.StatusNotFound)
+			return
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		gotQueries = append(gotQueries, body.Query)
+		rw.Write([]byte(`{
+			"data": {"repository": {
+				"i1": {"__typename": "Issue", "number": 1, "title": "a feature", "state": "CLOSED", "url": "https://github.example.com/my/repo/issues/1", "labels": {"nodes": [{"name": "enhancement"}]}},
+				"i2": {"__typename": "Issue", "number": 2, "title": "a bug", "state": "CLOSED", "url": "https://github.example.com/my/repo/issues/2", "labels": {"nodes": [{"name": "Bug"}]}},
+				"i3": {"__typename": "PullRequest", "number": 3, "title": "a pull request", "state": "MERGED", "url": "https://github.example.com/my/repo/pull/3", "labels": {"nodes": []}},
+				"i4": null
+			}},
+			"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to an issue or pull request with the number of 4."}]
+		}`))
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	issues, err := g.GetIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "my/repo"}}, []string{"1", "2", "3", "4", "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(gotQueries))
+	assert.Contains(t, gotQueries[0], `repository(owner: "my", name: "repo")`)
+	assert.NotContains(t, gotQueries[0], "issueType")
+	assert.Equal(t, 3, len(issues))
+	assert.Equal(t, entities.Issue{
+		IssueKey:     "1",
+		IssueSummary: "a feature",
+		IssueStatus:  "closed",
+		IssueType:    "issue",
+		Category:     entities.CLOSED_FEATURE,
+		WebURL:       "https://github.example.com/my/repo/issues/1",
+		Labels:       []string{"enhancement"},
+	}, issues[0])
+	assert.Equal(t, entities.FIXED_BUG, issues[1].Category)
+	assert.Equal(t, "pull_request", issues[2].IssueType)
+	assert.Equal(t, "merged", issues[2].IssueStatus)
+}
+
+func TestGitHubGetIssuesWithIssueTypes(t *testing.T) {
+	var gotQuery string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		gotQuery = body.Query
+		rw.Write([]byte(`{"data": {"repository": {
+			"i1": {"__typename": "Issue", "number": 1, "title": "a bug", "state": "CLOSED", "labels": {"nodes": [{"name": "feature"}]}, "issueType": {"name": "Bug"}},
+			"i2": {"__typename": "Issue", "number": 2, "title": "a task", "state": "CLOSED", "labels": {"nodes": [{"name": "docs"}]}, "issueType": {"name": "Task"}}
+		}}}`))
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token",
+		clients.WithGitHubRepository("other/tracker"),
+		clients.WithIssueTypeCategory("bug", entities.FIXED_BUG),
+		clients.WithGitHubLabelCategory("feature", entities.CLOSED_FEATURE),
+		clients.WithGitHubDefaultCategory(entities.OTHER))
+	assert.NoError(t, err)
+
+	issues, err := g.GetIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "123"}}, []string{"1", "2"})
+	assert.NoError(t, err)
+	assert.Contains(t, gotQuery, `repository(owner: "other", name: "tracker")`)
+	assert.Contains(t, gotQuery, "issueType { name }")
+	assert.Equal(t, 2, len(issues))
+	assert.Equal(t, "Bug", issues[0].IssueType)
+	assert.Equal(t, entities.FIXED_BUG, issues[0].Category)
+	assert.Equal(t, "Task", issues[1].IssueType)
+	assert.Equal(t, entities.OTHER, issues[1].Category)
+}
+
+func TestGitHubGetIssuesFailure(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"errors": [{"type": "FORBIDDEN", "message": "Resource not accessible by integration"}]}`))
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	_, err = g.GetIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "my/repo"}}, []string{"1"})
+	assert.Error(t, err)
+}
+
+func TestGitHubGetKnownIssues(t *testing.T) {
+	var gotQuery string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v3/search/issues" {
+			gotQuery = req.URL.Query().Get("q")
+			rw.Write([]byte(`{"total_count": 1, "items": [{"number": 5, "title": "a known bug", "state": "open", "html_url": "https://github.example.com/my/repo/issues/5", "labels": [{"name": "bug"}]}]}`))
+		} else {
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token",
+		clients.WithGitHubKnownIssues(entities.KnownIssuesFilter{Labels: []string{"bug", "known issue"}}))
+	assert.NoError(t, err)
+
+	repo := &entities.EnrichedRepo{Repo: entities.Repo{ID: "my/repo", KnownIssuesFilter: entities.KnownIssuesFilter{Milestone: "1.0"}}}
+	issues, err := g.GetKnownIssues(context.Background(), repo)
+	assert.NoError(t, err)
+	assert.Equal(t, `repo:my/repo is:issue is:open label:"bug" label:"known issue" milestone:"1.0"`, gotQuery)
+	assert.Equal(t, []entities.Issue{{
+		IssueKey:     "5",
+		IssueSummary: "a known bug",
+		IssueStatus:  "open",
+		IssueType:    "issue",
+		Category:     entities.FIXED_BUG,
+		WebURL:       "https://github.example.com/my/repo/issues/5",
+		Labels:       []string{"bug"},
+	}}, issues)
+
+	g, err = clients.NewGitHub(gitSrv.URL, "token")
+	assert.NoError(t, err)
+	issues, err = g.GetKnownIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "my/repo"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `repo:my/repo is:issue is:open label:"bug"`, gotQuery)
+	assert.Len(t, issues, 1)
+}
+
+func TestGitHubGetIssuesOfOtherRepositories(t *testing.T) {
+	var gotQueries []string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		gotQueries = append(gotQueries, body.Query)
+		if strings.Contains(body.Query, `owner: "platform"`) {
+			rw.Write([]byte(`{"data": {"repository": {"i12": {"__typename": "Issue", "number": 12, "title": "shared issue", "state": "CLOSED", "labels": {"nodes": []}}}}}`))
+			return
+		}
+		rw.Write([]byte(`{"data": {"repository": {"i1": {"__typename": "Issue", "number": 1, "title": "local issue", "state": "CLOSED", "labels": {"nodes": []}}}}}`))
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitHub(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	issues, err := g.GetIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "my/repo"}}, []string{"1", "platform/backlog#12"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(gotQueries))
+	assert.Contains(t, gotQueries[0], `repository(owner: "my", name: "repo")`)
+	assert.Contains(t, gotQueries[1], `repository(owner: "platform", name: "backlog")`)
+	assert.Equal(t, 2, len(issues))
+	assert.Equal(t, "1", issues[0].IssueKey)
+	assert.Equal(t, "platform/backlog#12", issues[1].IssueKey)
+	assert.Equal(t, "shared issue", issues[1].IssueSummary)
+}
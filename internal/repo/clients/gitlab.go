@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -11,13 +12,95 @@ import (
 	"github.com/xanzy/go-gitlab"
 )
 
+const (
+	defaultPageSize = 100
+	defaultMaxPages = 50
+)
+
 type Git struct {
-	c                     *gitlab.Client
-	issueLabelsForFeature []string
-	issueLabelsForBug     []string
+	c               *gitlab.Client
+	labelCategories []labelCategory
+	defaultCategory entities.IssueCategory
+	pageSize        int
+	maxPages        int
+	knownIssues     entities.KnownIssuesFilter
+}
+
+// labelCategory associates the issues having the label to the category. A label
+// ending with "::*" matches all the scoped labels of the scope.
+type labelCategory struct {
+	label    string
+	category entities.IssueCategory
+}
+
+func (lc labelCategory) match(label string) bool {
+	if scope, ok := strings.CutSuffix(lc.label, "::*"); ok {
+		s, _, found := strings.Cut(label, "::")
+		return found && strings.EqualFold(s, scope)
+	}
+
+	return strings.EqualFold(label, lc.label)
+}
+
+type GitLabOpt func(*Git)
+
+// WithPageSize sets the number of items requested for each page of the GitLab list calls.
+func WithPageSize(v int) GitLabOpt {
+	return func(g *Git) {
+		if v > 0 {
+			g.pageSize = v
+		}
+	}
+}
+
+// WithMaxPages sets the upper bound of pages retrieved for each GitLab list call.
+func WithMaxPages(v int) GitLabOpt {
+	return func(g *Git) {
+		if v > 0 {
+			g.maxPages = v
+		}
+	}
+}
+
+// WithKnownIssuesLabels sets the default labels of the open issues retrieved as known issues.
+func WithKnownIssuesLabels(labels []string) GitLabOpt {
+	return func(g *Git) {
+		g.knownIssues.Labels = labels
+	}
+}
+
+// WithKnownIssuesMilestone sets the default milestone of the open issues retrieved as known issues.
+func WithKnownIssuesMilestone(milestone string) GitLabOpt {
+	return func(g *Git) {
+		g.knownIssues.Milestone = milestone
+	}
+}
+
+// WithKnownIssuesType sets the default type (issue, incident, test_case) of the
+// open issues retrieved as known issues.
+func WithKnownIssuesType(issueType string) GitLabOpt {
+	return func(g *Git) {
+		g.knownIssues.IssueType = issueType
+	}
+}
+
+// WithLabelCategory associates the issues having the label to the category.
+// The labels are evaluated in the order they are added: the first one matching
+// a label of the issue determines the category.
+func WithLabelCategory(label string, category entities.IssueCategory) GitLabOpt {
+	return func(g *Git) {
+		g.labelCategories = append(g.labelCategories, labelCategory{label: strings.TrimSpace(label), category: category})
+	}
+}
+
+// WithDefaultCategory sets the category of the issues not matching any label.
+func WithDefaultCategory(category entities.IssueCategory) GitLabOpt {
+	return func(g *Git) {
+		g.defaultCategory = category
+	}
 }
 
-func NewGitLab(URL, token string) (Git, error) {
+func NewGitLab(URL, token string, opts ...GitLabOpt) (Git, error) {
 	c, err := gitlab.NewClient(token,
 		gitlab.WithBaseURL(fmt.Sprintf("%s/api/v4/", URL)))
 	if err != nil {
@@ -25,14 +108,47 @@ func NewGitLab(URL, token string) (Git, error) {
 	}
 
 	g := Git{
-		c:                     c,
-		issueLabelsForFeature: []string{"feature"},
-		issueLabelsForBug:     []string{"bug"},
+		c:               c,
+		defaultCategory: entities.OTHER,
+		pageSize:        defaultPageSize,
+		maxPages:        defaultMaxPages,
+	}
+	for _, o := range opts {
+		o(&g)
+	}
+	if len(g.labelCategories) == 0 {
+		g.labelCategories = []labelCategory{
+			{label: "feature", category: entities.CLOSED_FEATURE},
+			{label: "bug", category: entities.FIXED_BUG},
+		}
 	}
 
 	return g, nil
 }
 
+// paginate calls list for each page until the last page or the max number of
+// pages is reached, returning the items of all the retrieved pages.
+func paginate[T any](g Git, list func(opts gitlab.ListOptions) ([]T, *gitlab.Response, error)) ([]T, error) {
+	var items []T
+	opts := gitlab.ListOptions{Page: 1, PerPage: g.pageSize}
+	for i := 0; i < g.maxPages; i++ {
+		page, resp, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if resp == nil || resp.NextPage == 0 {
+			return items, nil
+		}
+		opts.Page = resp.NextPage
+	}
+
+	fmt.Printf("stopped after %d pages of %d items, results could be incomplete\n", g.maxPages, g.pageSize)
+
+	return items, nil
+}
+
 func (g Git) GetMergeRequests(id, targetBranch string, commits []entities.RepoRecord) ([]entities.RepoRecord, error) {
 	if len(commits) == 0 {
 		return nil, nil
@@ -42,30 +158,77 @@ func (g Git) GetMergeRequests(id, targetBranch string, commits []entities.RepoRe
 	for _, c := range commits {
 		lookForCommit[c.ID] = true
 	}
-	opts := &gitlab.ListProjectMergeRequestsOptions{
-		UpdatedAfter: commits[0].CreatedAt,
-		State:        gitlab.String("merged"),
-		TargetBranch: gitlab.String(targetBranch),
+	mrs, err := paginate(g, func(lo gitlab.ListOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+		return g.c.MergeRequests.ListProjectMergeRequests(id, &gitlab.ListProjectMergeRequestsOptions{
+			ListOptions:  lo,
+			UpdatedAfter: commits[0].CreatedAt,
+			State:        gitlab.String("merged"),
+			TargetBranch: gitlab.String(targetBranch),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
-	mrs, _, err := g.c.MergeRequests.ListProjectMergeRequests(id, opts)
 
 	cs := make([]entities.RepoRecord, 0, len(mrs))
 	for _, mr := range mrs {
-		if !lookForCommit[mr.SHA] {
+		commitIDs, err := g.mergedCommits(id, mr, lookForCommit)
+		if err != nil {
+			return nil, err
+		}
+		if len(commitIDs) == 0 {
 			continue
 		}
+		var mergeCommitIDs []string
+		for _, sha := range []string{mr.MergeCommitSHA, mr.SquashCommitSHA} {
+			if sha != "" {
+				mergeCommitIDs = append(mergeCommitIDs, sha)
+			}
+		}
 		cs = append(cs, entities.RepoRecord{
-			ID:        strconv.Itoa(mr.ID),
-			ShortID:   strconv.Itoa(mr.IID),
-			Title:     mr.Title,
-			Message:   mr.Description,
-			CreatedAt: mr.MergedAt,
-			WebURL:    mr.WebURL,
-			Origin:    "merge_request",
+			ID:             strconv.Itoa(mr.ID),
+			ShortID:        strconv.Itoa(mr.IID),
+			Title:          mr.Title,
+			Message:        mr.Description,
+			CreatedAt:      mr.MergedAt,
+			WebURL:         mr.WebURL,
+			Origin:         "merge_request",
+			CommitIDs:      commitIDs,
+			MergeCommitIDs: mergeCommitIDs,
 		})
 	}
 
-	return cs, err
+	return cs, nil
+}
+
+// mergedCommits returns the ids of the commits in lookForCommit merged by the
+// merge request. The merge request is matched through its merge or squash
+// commit and, only for the fast-forward merges without them, through its
+// commits.
+func (g Git) mergedCommits(id string, mr *gitlab.MergeRequest, lookForCommit map[string]bool) ([]string, error) {
+	var commitIDs []string
+	for _, sha := range []string{mr.MergeCommitSHA, mr.SquashCommitSHA, mr.SHA} {
+		if sha != "" && lookForCommit[sha] && !slices.Contains(commitIDs, sha) {
+			commitIDs = append(commitIDs, sha)
+		}
+	}
+	if mr.MergeCommitSHA != "" || mr.SquashCommitSHA != "" {
+		return commitIDs, nil
+	}
+
+	mrCommits, err := paginate(g, func(lo gitlab.ListOptions) ([]*gitlab.Commit, *gitlab.Response, error) {
+		return g.c.MergeRequests.GetMergeRequestCommits(id, mr.IID, (*gitlab.GetMergeRequestCommitsOptions)(&lo))
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range mrCommits {
+		if lookForCommit[c.ID] && !slices.Contains(commitIDs, c.ID) {
+			commitIDs = append(commitIDs, c.ID)
+		}
+	}
+
+	return commitIDs, nil
 }
 
 func (g Git) GetCommits(id, from, to string) ([]entities.RepoRecord, error) {
@@ -76,8 +239,17 @@ func (g Git) GetCommits(id, from, to string) ([]entities.RepoRecord, error) {
 		return nil, err
 	}
 
+	gcs := c.Commits
+	if c.CompareTimeout {
+		fmt.Printf("compare from %s to %s timed out, listing the commits of the range instead\n", from, to)
+		gcs, err = g.listCommits(id, from, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var commits []entities.RepoRecord
-	for _, commit := range c.Commits {
+	for _, commit := range gcs {
 		commits = append(commits, entities.RepoRecord{
 			ID:        commit.ID,
 			ShortID:   commit.ShortID,
@@ -93,6 +265,28 @@ func (g Git) GetCommits(id, from, to string) ([]entities.RepoRecord, error) {
 
 }
 
+// listCommits returns the commits of the range from..to ordered from the
+// oldest, as returned by the compare API.
+func (g Git) listCommits(id, from, to string) ([]*gitlab.Commit, error) {
+	refName := to
+	if from != "" {
+		refName = fmt.Sprintf("%s..%s", from, to)
+	}
+
+	gcs, err := paginate(g, func(lo gitlab.ListOptions) ([]*gitlab.Commit, *gitlab.Response, error) {
+		return g.c.Commits.ListCommits(id, &gitlab.ListCommitsOptions{ListOptions: lo, RefName: &refName})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(gcs)-1; i < j; i, j = i+1, j-1 {
+		gcs[i], gcs[j] = gcs[j], gcs[i]
+	}
+
+	return gcs, nil
+}
+
 type ProjectResponse struct {
 	WebURL string `json:"web_url"`
 }
@@ -124,56 +318,107 @@ func (g Git) GetReleaseURL(gitRepoID, version string) (string, error) {
 	return releaseURL, nil
 }
 
+// GetIssues retrieves the issues with the keys ids from the project of the repo
+// or, for the keys project#iid, from the referenced project.
 func (g Git) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, ids []string) ([]entities.Issue, error) {
-	intArray := make([]int, len(ids))
-	for i, str := range ids {
-		num, err := strconv.Atoi(str)
+	var projects []string
+	iids := map[string][]int{}
+	for _, id := range ids {
+		project, key := entities.SplitIssueRef(id)
+		num, err := strconv.Atoi(key)
 		if err != nil {
 			return nil, err
 		}
-		intArray[i] = num
+		if _, ok := iids[project]; !ok {
+			projects = append(projects, project)
+		}
+		iids[project] = append(iids[project], num)
 	}
-	issues, _, err := g.c.Issues.ListProjectIssues(repo.ID, &gitlab.ListProjectIssuesOptions{IIDs: &intArray})
-	if err != nil {
-		return nil, err
+
+	var issueDetails []entities.Issue
+	for _, project := range projects {
+		pid := project
+		if pid == "" {
+			pid = repo.ID
+		}
+		projectIIDs := iids[project]
+		issues, err := paginate(g, func(lo gitlab.ListOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+			return g.c.Issues.ListProjectIssues(pid, &gitlab.ListProjectIssuesOptions{ListOptions: lo, IIDs: &projectIIDs})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range g.toIssues(issues) {
+			issue.IssueKey = entities.IssueRef(project, issue.IssueKey)
+			issueDetails = append(issueDetails, issue)
+		}
 	}
 
+	return issueDetails, nil
+}
+
+func (g Git) toIssues(issues []*gitlab.Issue) []entities.Issue {
 	var issueDetails []entities.Issue
 	for _, issue := range issues {
+		issueType := ""
+		if issue.IssueType != nil {
+			issueType = *issue.IssueType
+		}
 		issueDetails = append(issueDetails, entities.Issue{
 			IssueKey:     strconv.Itoa(issue.IID),
 			IssueSummary: issue.Title,
 			IssueStatus:  issue.State,
-			IssueType:    *issue.IssueType,
+			IssueType:    issueType,
 			Category:     g.extractIssueCategory(*issue),
 			WebURL:       issue.WebURL,
+			Labels:       issue.Labels,
 		})
 	}
 
-	return issueDetails, nil
+	return issueDetails
 }
 
 func (g Git) extractIssueCategory(gi gitlab.Issue) entities.IssueCategory {
-	for _, gil := range g.issueLabelsForFeature {
-		for _, label := range gi.Labels {
-			if strings.EqualFold(label, gil) {
-				return entities.CLOSED_FEATURE
-			}
-		}
-	}
-
-	for _, gil := range g.issueLabelsForBug {
+	for _, lc := range g.labelCategories {
 		for _, label := range gi.Labels {
-			if strings.EqualFold(label, gil) {
-				return entities.FIXED_BUG
+			if lc.match(label) {
+				return lc.category
 			}
 		}
 	}
 
-	return entities.CLOSED_FEATURE
+	return g.defaultCategory
 }
 
+// GetKnownIssues retrieves the open issues of the repo matching the known
+// issues filter. Nothing is retrieved when no filter criteria is configured.
 func (g Git) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
-	fmt.Printf("retrieving known issues using GitLab has not been implemented yet\n")
-	return nil, nil
+	f := repo.KnownIssuesFilter.WithDefaults(g.knownIssues)
+	if f.IsEmpty() {
+		return nil, nil
+	}
+
+	opts := gitlab.ListProjectIssuesOptions{State: gitlab.String("opened")}
+	if len(f.Labels) > 0 {
+		labels := gitlab.Labels(f.Labels)
+		opts.Labels = &labels
+	}
+	if f.Milestone != "" {
+		opts.Milestone = gitlab.String(f.Milestone)
+	}
+	if f.IssueType != "" {
+		opts.IssueType = gitlab.String(f.IssueType)
+	}
+	fmt.Printf("\nretrieving known issues using GitLab open issues with labels %v, milestone \"%s\" and type \"%s\"\n", f.Labels, f.Milestone, f.IssueType)
+
+	issues, err := paginate(g, func(lo gitlab.ListOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+		opts.ListOptions = lo
+		return g.c.Issues.ListProjectIssues(repo.ID, &opts, gitlab.WithContext(ctx))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list known issues: %w", err)
+	}
+
+	return g.toIssues(issues), nil
 }
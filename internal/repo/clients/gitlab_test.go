@@ -1,10 +1,13 @@
 package clients_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -110,7 +113,9 @@ func TestGetCommits(t *testing.T) {
 func TestGetMergeRequests(t *testing.T) {
 	gotParams := url.Values{}
 	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		if req.URL.Path == "/api/v4/projects/1/merge_requests" {
+		if strings.HasPrefix(req.URL.Path, "/api/v4/projects/1/merge_requests/") {
+			rw.Write([]byte(`[]`))
+		} else if req.URL.Path == "/api/v4/projects/1/merge_requests" {
 			gotParams = req.URL.Query()
 			rw.Write([]byte(`[
 					{
@@ -186,3 +191,239 @@ func TestGetMergeRequests(t *testing.T) {
 	assert.Equal(t, wantMr.WebURL, mrs[0].WebURL)
 	assert.Equal(t, wantMr.Origin, mrs[0].Origin)
 }
+
+func TestGetMergeRequestsPagination(t *testing.T) {
+	var gotPages []string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, "/api/v4/projects/1/merge_requests/") {
+			rw.Write([]byte(`[]`))
+			return
+		}
+		if req.URL.Path != "/api/v4/projects/1/merge_requests" {
+			http.Error(rw, "Not found", http.StatusNotFound)
+			return
+		}
+		params := req.URL.Query()
+		gotPages = append(gotPages, params.Get("page"))
+		assert.Equal(t, "1", params.Get("per_page"))
+		page := params.Get("page")
+		if page != "3" {
+			next, _ := strconv.Atoi(page)
+			rw.Header().Set("X-Next-Page", strconv.Itoa(next+1))
+		}
+		rw.Write([]byte(fmt.Sprintf(`[{"id": 1%s, "iid": %s, "title": "mr %s", "sha": "commit%s"}]`, page, page, page, page)))
+	}))
+	defer gitSrv.Close()
+
+	commits := []entities.RepoRecord{{ID: "commit1"}, {ID: "commit2"}, {ID: "commit3"}}
+
+	t.Run("all pages", func(t *testing.T) {
+		gotPages = nil
+		g, err := clients.NewGitLab(gitSrv.URL, "token", clients.WithPageSize(1))
+		assert.NoError(t, err)
+
+		mrs, err := g.GetMergeRequests("1", "main", commits)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2", "3"}, gotPages)
+		assert.Equal(t, 3, len(mrs))
+	})
+
+	t.Run("max pages", func(t *testing.T) {
+		gotPages = nil
+		g, err := clients.NewGitLab(gitSrv.URL, "token", clients.WithPageSize(1), clients.WithMaxPages(2))
+		assert.NoError(t, err)
+
+		mrs, err := g.GetMergeRequests("1", "main", commits)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, gotPages)
+		assert.Equal(t, 2, len(mrs))
+	})
+}
+
+func TestGetCommitsCompareTimeout(t *testing.T) {
+	var gotRefName string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v4/projects/1/repository/compare":
+			rw.Write([]byte(`{"compare_timeout": true, "commits": []}`))
+		case "/api/v4/projects/1/repository/commits":
+			gotRefName = req.URL.Query().Get("ref_name")
+			rw.Write([]byte(`[{"id": "2", "title": "newest"}, {"id": "1", "title": "oldest"}]`))
+		default:
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	commits, err := g.GetCommits("1", "0.0.0", "0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.0..0.0.1", gotRefName)
+	assert.Equal(t, 2, len(commits))
+	assert.Equal(t, "1", commits[0].ID)
+	assert.Equal(t, "2", commits[1].ID)
+}
+
+func TestGetMergeRequestsAssociatedCommits(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v4/projects/1/merge_requests":
+			rw.Write([]byte(`[
+				{"id": 10, "iid": 1, "title": "squashed", "sha": "head1", "squash_commit_sha": "commit1"},
+				{"id": 11, "iid": 2, "title": "fast-forward", "sha": "head2"},
+				{"id": 12, "iid": 3, "title": "another release", "sha": "head3", "merge_commit_sha": "merge3"}
+			]`))
+		case "/api/v4/projects/1/merge_requests/2/commits":
+			rw.Write([]byte(`[{"id": "commit2"}, {"id": "commit3"}]`))
+		case "/api/v4/projects/1/merge_requests/1/commits", "/api/v4/projects/1/merge_requests/3/commits":
+			t.Errorf("unexpected request %s of the commits of a merge request with a merge or squash commit", req.URL.Path)
+			rw.Write([]byte(`[{"id": "commit2"}]`))
+		default:
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	commits := []entities.RepoRecord{{ID: "commit1"}, {ID: "commit2"}, {ID: "commit3"}}
+	mrs, err := g.GetMergeRequests("1", "main", commits)
+	assert.NoError(t, err)
+
+	if len(mrs) != 2 {
+		t.Fatalf("Expected %d merge requests, got %d", 2, len(mrs))
+	}
+	assert.Equal(t, "1", mrs[0].ShortID)
+	assert.Equal(t, []string{"commit1"}, mrs[0].CommitIDs)
+	assert.Equal(t, "2", mrs[1].ShortID)
+	assert.Equal(t, []string{"commit2", "commit3"}, mrs[1].CommitIDs)
+}
+
+func TestGetKnownIssues(t *testing.T) {
+	var query url.Values
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v4/projects/1/issues" {
+			query = req.URL.Query()
+			rw.Write([]byte(`[
+				{"id": 13, "iid": 3, "title": "known bug", "state": "opened", "issue_type": "issue", "labels": ["bug"], "web_url": "https://gitlab.example.com/my/repo/-/issues/3"},
+				{"id": 14, "iid": 4, "title": "known limitation", "state": "opened", "issue_type": "incident", "labels": ["limitation"], "web_url": "https://gitlab.example.com/my/repo/-/issues/4"}
+			]`))
+		} else {
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitLab(gitSrv.URL, "token",
+		clients.WithKnownIssuesLabels([]string{"known-issue"}),
+		clients.WithKnownIssuesMilestone("1.0"))
+	assert.NoError(t, err)
+
+	repo := &entities.EnrichedRepo{Repo: entities.Repo{ID: "1", KnownIssuesFilter: entities.KnownIssuesFilter{
+		Labels:    []string{"bug", "limitation"},
+		IssueType: "issue",
+	}}}
+	issues, err := g.GetKnownIssues(context.Background(), repo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "opened", query.Get("state"))
+	assert.Equal(t, "bug,limitation", query.Get("labels"))
+	assert.Equal(t, "1.0", query.Get("milestone"))
+	assert.Equal(t, "issue", query.Get("issue_type"))
+
+	expected := []entities.Issue{
+		{IssueKey: "3", IssueSummary: "known bug", IssueStatus: "opened", IssueType: "issue", Category: entities.FIXED_BUG, WebURL: "https://gitlab.example.com/my/repo/-/issues/3", Labels: []string{"bug"}},
+		{IssueKey: "4", IssueSummary: "known limitation", IssueStatus: "opened", IssueType: "incident", Category: entities.OTHER, WebURL: "https://gitlab.example.com/my/repo/-/issues/4", Labels: []string{"limitation"}},
+	}
+	assert.Equal(t, expected, issues)
+}
+
+func TestGetKnownIssuesWithoutFilter(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Errorf("unexpected request %s", req.URL.Path)
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	issues, err := g.GetKnownIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "1"}})
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestGetIssuesLabelCategories(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v4/projects/1/issues" {
+			rw.Write([]byte(`[
+				{"id": 11, "iid": 1, "title": "scoped bug", "state": "closed", "issue_type": "issue", "labels": ["type::bug", "feature"]},
+				{"id": 12, "iid": 2, "title": "feature", "state": "closed", "issue_type": "issue", "labels": ["Feature"]},
+				{"id": 13, "iid": 3, "title": "chore", "state": "closed", "issue_type": "issue", "labels": ["chore"]},
+				{"id": 14, "iid": 4, "title": "scoped feature", "state": "closed", "issue_type": "issue", "labels": ["kind::story"]}
+			]`))
+		} else {
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitLab(gitSrv.URL, "token",
+		clients.WithLabelCategory("type::bug", entities.FIXED_BUG),
+		clients.WithLabelCategory("feature", entities.CLOSED_FEATURE),
+		clients.WithLabelCategory("kind::*", entities.CLOSED_FEATURE))
+	assert.NoError(t, err)
+
+	issues, err := g.GetIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "1"}}, []string{"1", "2", "3", "4"})
+	assert.NoError(t, err)
+
+	categories := map[string]entities.IssueCategory{}
+	for _, i := range issues {
+		categories[i.IssueKey] = i.Category
+	}
+	assert.Equal(t, map[string]entities.IssueCategory{
+		"1": entities.FIXED_BUG,
+		"2": entities.CLOSED_FEATURE,
+		"3": entities.OTHER,
+		"4": entities.CLOSED_FEATURE,
+	}, categories)
+
+	g, err = clients.NewGitLab(gitSrv.URL, "token", clients.WithDefaultCategory(entities.CLOSED_FEATURE))
+	assert.NoError(t, err)
+
+	issues, err = g.GetIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "1"}}, []string{"1", "2", "3", "4"})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", issues[2].IssueKey)
+	assert.Equal(t, entities.CLOSED_FEATURE, issues[2].Category)
+}
+
+func TestGetIssuesOfOtherProjects(t *testing.T) {
+	var gotPaths []string
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotPaths = append(gotPaths, req.URL.EscapedPath()+"?"+req.URL.Query().Get("iids[]"))
+		switch req.URL.EscapedPath() {
+		case "/api/v4/projects/1/issues":
+			rw.Write([]byte(`[{"id": 11, "iid": 1, "title": "local issue", "state": "closed", "labels": ["bug"]}]`))
+		case "/api/v4/projects/platform%2Fbacklog/issues":
+			rw.Write([]byte(`[{"id": 22, "iid": 12, "title": "shared issue", "state": "closed", "labels": ["feature"], "web_url": "https://gitlab.example.com/platform/backlog/-/issues/12"}]`))
+		default:
+			http.Error(rw, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	g, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	issues, err := g.GetIssues(context.Background(), &entities.EnrichedRepo{Repo: entities.Repo{ID: "1"}}, []string{"1", "platform/backlog#12"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/api/v4/projects/1/issues?1", "/api/v4/projects/platform%2Fbacklog/issues?12"}, gotPaths)
+	assert.Equal(t, 2, len(issues))
+	assert.Equal(t, "1", issues[0].IssueKey)
+	assert.Equal(t, entities.FIXED_BUG, issues[0].Category)
+	assert.Equal(t, "platform/backlog#12", issues[1].IssueKey)
+	assert.Equal(t, "shared issue", issues[1].IssueSummary)
+	assert.Equal(t, "https://gitlab.example.com/platform/backlog/-/issues/12", issues[1].WebURL)
+}
@@ -0,0 +1,249 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/happyagosmith/jig/internal/entities"
+)
+
+const (
+	fieldSeparator  = "\x1f"
+	recordSeparator = "\x1e"
+	logFormat       = "--format=%H%x1f%h%x1f%cI%x1f%B%x1e"
+)
+
+var mergeRequestRefs = []*regexp.Regexp{
+	regexp.MustCompile(`See merge request \S*!(\d+)`),
+	regexp.MustCompile(`^Merge pull request #(\d+)`),
+}
+
+// Local reads the commits from a git working tree available on the file system,
+// without using any API. The id of the repo is the path of the working tree,
+// relative to the base path if it is not absolute.
+type Local struct {
+	basePath string
+}
+
+func NewLocal(basePath string) (Local, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return Local{}, fmt.Errorf("git executable is required to read local repositories: %w", err)
+	}
+
+	return Local{basePath: basePath}, nil
+}
+
+func (l Local) path(id string) string {
+	if filepath.IsAbs(id) || l.basePath == "" {
+		return id
+	}
+
+	return filepath.Join(l.basePath, id)
+}
+
+func (l Local) git(id string, stdin string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", l.path(id)}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed on %s: %s", strings.Join(args, " "), l.path(id), strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+func parseLog(out string) []entities.RepoRecord {
+	var commits []entities.RepoRecord
+	for _, record := range strings.Split(out, recordSeparator) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		f := strings.SplitN(record, fieldSeparator, 4)
+		if len(f) != 4 {
+			continue
+		}
+
+		message := strings.TrimRight(f[3], "\n")
+		c := entities.RepoRecord{
+			ID:      f[0],
+			ShortID: f[1],
+			Title:   strings.Split(message, "\n")[0],
+			Message: message,
+			Origin:  "commit",
+		}
+		if t, err := time.Parse(time.RFC3339, f[2]); err == nil {
+			t = t.UTC()
+			c.CreatedAt = &t
+		}
+
+		commits = append(commits, c)
+	}
+
+	return commits
+}
+
+func (l Local) GetCommits(id, from, to string) ([]entities.RepoRecord, error) {
+	rev := to
+	if from != "" {
+		rev = fmt.Sprintf("%s..%s", from, to)
+	}
+
+	// the revisions come from the model, so they are never parsed as options
+	out, err := l.git(id, "", "log", "--reverse", logFormat, "--end-of-options", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLog(out), nil
+}
+
+// GetMergeRequests derives a merge request record from each merge commit
+// included in commits that has been merged on the first-parent history of targetBranch.
+func (l Local) GetMergeRequests(id, targetBranch string, commits []entities.RepoRecord) ([]entities.RepoRecord, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	out, err := l.git(id, "", "rev-list", "--first-parent", "--merges", "--end-of-options", targetBranch, "--")
+	if err != nil {
+		return nil, err
+	}
+	onTargetBranch := map[string]bool{}
+	for _, sha := range strings.Fields(out) {
+		onTargetBranch[sha] = true
+	}
+
+	var shas []string
+	for _, c := range commits {
+		if onTargetBranch[c.ID] {
+			shas = append(shas, c.ID)
+		}
+	}
+	if len(shas) == 0 {
+		return nil, nil
+	}
+
+	out, err = l.git(id, strings.Join(shas, "\n")+"\n", "log", "--no-walk=unsorted", "--stdin", logFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	var mrs []entities.RepoRecord
+	for _, c := range parseLog(out) {
+		mr := toMergeRequest(c)
+		merged, err := l.git(id, "", "rev-list", fmt.Sprintf("%s^1..%s", c.ID, c.ID), "--")
+		if err != nil {
+			return nil, err
+		}
+		mr.CommitIDs = strings.Fields(merged)
+		mr.MergeCommitIDs = []string{c.ID}
+		mrs = append(mrs, mr)
+	}
+
+	return mrs, nil
+}
+
+// toMergeRequest builds the merge request record from the merge commit: the
+// title is the first line of the commit body, as written by GitLab and GitHub
+// when merging, and the number is extracted from the merge commit message.
+func toMergeRequest(c entities.RepoRecord) entities.RepoRecord {
+	mr := entities.RepoRecord{
+		ID:        c.ID,
+		ShortID:   c.ShortID,
+		Title:     c.Title,
+		Message:   c.Message,
+		CreatedAt: c.CreatedAt,
+		Origin:    "merge_request",
+	}
+
+	for _, re := range mergeRequestRefs {
+		if m := re.FindStringSubmatch(c.Message); m != nil {
+			mr.ShortID = m[1]
+			break
+		}
+	}
+
+	_, body, found := strings.Cut(c.Message, "\n")
+	if !found {
+		return mr
+	}
+	body = strings.TrimLeft(body, "\n")
+	if body == "" {
+		return mr
+	}
+
+	mr.Title = strings.Split(body, "\n")[0]
+	mr.Message = body
+
+	return mr
+}
+
+func (l Local) GetRepoURL(id string) (string, error) {
+	out, err := l.git(id, "", "remote", "get-url", "origin")
+	if err != nil {
+		fmt.Printf("no origin remote found for the local repository %s\n", l.path(id))
+		return "", nil
+	}
+
+	return remoteWebURL(strings.TrimSpace(out)), nil
+}
+
+var scpLikeRemote = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// remoteWebURL returns the https web URL of the repository of the remote URL,
+// e.g. https://gitlab.example.com/group/repo for git@gitlab.example.com:group/repo.git.
+// The remotes that are local paths have no web URL.
+func remoteWebURL(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		switch u.Scheme {
+		case "http", "https", "ssh", "git", "git+ssh", "ssh+git":
+			if u.Scheme != "http" {
+				u.Scheme = "https"
+			}
+			// the port of ssh and git is not the one of the web server
+			if u.Scheme == "https" && u.Port() != "" && !strings.HasPrefix(remote, "https:") {
+				u.Host = u.Hostname()
+			}
+			u.User = nil
+			return u.String()
+		default:
+			return ""
+		}
+	}
+
+	if m := scpLikeRemote.FindStringSubmatch(remote); m != nil {
+		return fmt.Sprintf("https://%s/%s", m[1], strings.TrimPrefix(m[2], "/"))
+	}
+
+	return ""
+}
+
+func (l Local) GetReleaseURL(id, tag string) (string, error) {
+	return "", nil
+}
+
+func (l Local) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, ids []string) ([]entities.Issue, error) {
+	fmt.Printf("retrieving issues is not available for local repositories\n")
+	return nil, entities.ErrIssuesNotSupported
+}
+
+func (l Local) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
+	fmt.Printf("retrieving known issues is not available for local repositories\n")
+	return nil, nil
+}
@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/happyagosmith/jig/internal/entities"
+)
+
+// ProfileTracker dispatches the issue requests to the repo tracker configured
+// for the git profile of the repo, falling back to the default repo tracker.
+type ProfileTracker struct {
+	defaultTracker entities.IssuesTracker
+	trackers       map[string]entities.IssuesTracker
+}
+
+func NewProfileTracker(defaultTracker entities.IssuesTracker, trackers map[string]entities.IssuesTracker) ProfileTracker {
+	return ProfileTracker{defaultTracker: defaultTracker, trackers: trackers}
+}
+
+func (p ProfileTracker) tracker(repo *entities.EnrichedRepo) (entities.IssuesTracker, error) {
+	if repo.GitProfile == "" {
+		if p.defaultTracker == nil {
+			return nil, fmt.Errorf("default git repo tracker not configured for the repo %s", repo.Label)
+		}
+		return p.defaultTracker, nil
+	}
+
+	t, ok := p.trackers[repo.GitProfile]
+	if !ok {
+		return nil, fmt.Errorf("git profile %q of the repo %s not configured", repo.GitProfile, repo.Label)
+	}
+
+	return t, nil
+}
+
+func (p ProfileTracker) GetIssues(ctx context.Context, repo *entities.EnrichedRepo, ids []string) ([]entities.Issue, error) {
+	t, err := p.tracker(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.GetIssues(ctx, repo, ids)
+}
+
+func (p ProfileTracker) GetKnownIssues(ctx context.Context, repo *entities.EnrichedRepo) ([]entities.Issue, error) {
+	t, err := p.tracker(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.GetKnownIssues(ctx, repo)
+}
@@ -2,6 +2,8 @@ package repo
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/happyagosmith/jig/internal/entities"
 	"github.com/happyagosmith/jig/internal/parsers"
@@ -13,6 +15,8 @@ type Repo struct {
 	customParser          *parsers.CustomParser
 	closingPattern        parsers.ClosingPatternParser
 	keepCCWithoutScope    bool
+	scopeSeparators       string
+	typeCategories        map[string]entities.CommitCategory
 	repoClient            entities.RepoClient
 	defaultMRTargetBranch string
 }
@@ -25,6 +29,24 @@ func WithKeepCCWithoutScope(v bool) RepoParserOpt {
 	}
 }
 
+func WithScopeSeparators(v string) RepoParserOpt {
+	return func(r *Repo) {
+		if v != "" {
+			r.scopeSeparators = v
+		}
+	}
+}
+
+// WithTypeCategory sets the category of the conventional commits of the type,
+// in addition to the feat and fix types or overriding them.
+func WithTypeCategory(t string, category entities.CommitCategory) RepoParserOpt {
+	return func(r *Repo) {
+		if t != "" {
+			r.typeCategories[strings.ToLower(t)] = category
+		}
+	}
+}
+
 func WithCustomPattern(v string) RepoParserOpt {
 	return func(r *Repo) {
 		if v != "" {
@@ -53,15 +75,22 @@ func New(client entities.RepoClient, issuePatterns []parsers.IssuePattern, opts
 	}
 
 	g := Repo{
-		conventionalParser: parsers.NewConventionalCommit(),
-		itParser:           parsers.NewIssueExtractor(itOpts...),
-		closingPattern:     parsers.NewClosingPattern(cpOpts...),
-		repoClient:         client,
+		itParser:        parsers.NewIssueExtractor(itOpts...),
+		closingPattern:  parsers.NewClosingPattern(cpOpts...),
+		scopeSeparators: parsers.DefaultScopeSeparators,
+		typeCategories:  map[string]entities.CommitCategory{},
+		repoClient:      client,
 	}
 	for _, o := range opts {
 		o(&g)
 	}
 
+	ccOpts := make([]parsers.CCParserOpt, 0, len(g.typeCategories))
+	for t, category := range g.typeCategories {
+		ccOpts = append(ccOpts, parsers.WithTypeCategory(t, category))
+	}
+	g.conventionalParser = parsers.NewConventionalCommit(ccOpts...)
+
 	return g, nil
 }
 
@@ -71,32 +100,57 @@ func (r Repo) GetParsedRecords(id, from, to, mrTargetBranch string) ([]entities.
 		return nil, err
 	}
 
-	pcommits, err := r.parse(commits)
-	if err != nil {
-		return nil, err
-	}
-
 	targetBranch := mrTargetBranch
 	if targetBranch == "" {
 		targetBranch = r.defaultMRTargetBranch
 	}
-	if targetBranch == "" {
-		return pcommits, nil
+	var mr []entities.RepoRecord
+	if targetBranch != "" {
+		mr, err = r.repoClient.GetMergeRequests(id, targetBranch, commits)
+		if err != nil {
+			return nil, err
+		}
 	}
-	mr, err := r.repoClient.GetMergeRequests(id, targetBranch, commits)
+
+	reverted := revertedRecords(slices.Concat(commits, mr))
+	pcommits, err := r.parse(commits, reverted)
 	if err != nil {
 		return nil, err
 	}
-	pmr, err := r.parse(mr)
+	if targetBranch == "" {
+		return pcommits, nil
+	}
+	pmr, err := r.parse(mr, reverted)
 	if err != nil {
 		return nil, err
 	}
 
+	linkMergeRequests(pcommits, mr)
+
 	pcommits = append(pcommits, pmr...)
 
 	return pcommits, nil
 }
 
+// linkMergeRequests sets on each parsed commit the merge request that merged it.
+func linkMergeRequests(pcommits []entities.ParsedRepoRecord, mrs []entities.RepoRecord) {
+	mergedBy := map[string]entities.RepoRecord{}
+	for _, mr := range mrs {
+		for _, id := range mr.CommitIDs {
+			mergedBy[id] = mr
+		}
+	}
+
+	for i := range pcommits {
+		mr, ok := mergedBy[pcommits[i].ID]
+		if !ok {
+			continue
+		}
+		pcommits[i].MergeRequestID = mr.ShortID
+		pcommits[i].MergeRequestURL = mr.WebURL
+	}
+}
+
 func (r Repo) GetReleaseURL(id, tag string) (string, error) {
 	return r.repoClient.GetReleaseURL(id, tag)
 }
@@ -105,13 +159,31 @@ func (r Repo) GetRepoURL(id string) (string, error) {
 	return r.repoClient.GetRepoURL(id)
 }
 
-func (r Repo) parse(commits []entities.RepoRecord) ([]entities.ParsedRepoRecord, error) {
+func (r Repo) parse(commits []entities.RepoRecord, reverted map[string]bool) ([]entities.ParsedRepoRecord, error) {
 	found := map[string]bool{}
 	var cds []entities.ParsedRepoRecord
 
 	for _, commit := range commits {
 		fmt.Printf("parsing %s \n", commit.String())
-		cd := entities.ParsedRepoRecord{RepoRecord: commit}
+		if isReverted(commit, reverted) {
+			fmt.Printf("skipped %s, reverted in the same release \n", commit.String())
+			continue
+		}
+		if revert := parsers.ParseRevert(commit.Title, commit.Message); revert != nil {
+			for _, cd := range r.parseRevert(commit, *revert) {
+				cds = append(cds, cd)
+				fmt.Printf("extracted %s \n", cd.String())
+			}
+			continue
+		}
+		message := commit.Message
+		if !strings.HasPrefix(message, commit.Title) {
+			message = commit.Title + "\n\n" + message
+		}
+		trailers := parsers.ParseTrailers(message)
+		breakingChange, trailerValues := footer(trailers)
+
+		cd := entities.ParsedRepoRecord{RepoRecord: commit, BreakingChange: breakingChange, Trailers: trailerValues}
 		cd.Parser = "conventionalParser"
 
 		cc := r.conventionalParser.Parse(commit.Title)
@@ -120,23 +192,24 @@ func (r Repo) parse(commits []entities.RepoRecord) ([]entities.ParsedRepoRecord,
 			cd.Parser = "customParser"
 		}
 		if cc != nil && (cc.Scope != "" || (r.keepCCWithoutScope && cc.Category != entities.UNKNOWN && cc.Subject != "")) {
-			cd.ParsedKey = cc.Scope
 			cd.ParsedCategory = cc.Category
 			cd.ParsedSummary = cc.Subject
-			cd.IsBreakingChange = cc.IsBreaking
+			cd.IsBreakingChange = cc.IsBreaking || breakingChange != ""
 			cd.ParsedType = cc.Type
-			issueDetails := r.itParser.Parse(cc.Scope)
-			cd.ParsedKey = issueDetails.Key
-			cd.ParsedIssueTracker = issueDetails.IssueTracker
-			if cd.ParsedKey == "" {
-				cds = append(cds, cd)
-				fmt.Printf("added conventional commit without issueKey \"%s\" \n", cd.Message)
-				continue
-			}
-			if !found[cd.ParsedKey] {
-				found[cd.ParsedKey] = true
-				cds = append(cds, cd)
-				fmt.Printf("extracted %s \n", cd.String())
+			for _, issueDetails := range r.scopeIssues(*cc) {
+				cd.ParsedKey = issueDetails.Ref()
+				cd.ParsedProject = issueDetails.Project
+				cd.ParsedIssueTracker = issueDetails.IssueTracker
+				if cd.ParsedKey == "" {
+					cds = append(cds, cd)
+					fmt.Printf("added conventional commit without issueKey \"%s\" \n", cd.Message)
+					continue
+				}
+				if !found[cd.ParsedKey] {
+					found[cd.ParsedKey] = true
+					cds = append(cds, cd)
+					fmt.Printf("extracted %s \n", cd.String())
+				}
 			}
 		}
 
@@ -145,18 +218,243 @@ func (r Repo) parse(commits []entities.RepoRecord) ([]entities.ParsedRepoRecord,
 			issueDetails := r.itParser.Parse(c.Key)
 			cd := entities.ParsedRepoRecord{
 				RepoRecord:         commit,
-				ParsedKey:          issueDetails.Key,
+				ParsedKey:          issueDetails.Ref(),
+				ParsedProject:      issueDetails.Project,
 				ParsedIssueTracker: issueDetails.IssueTracker,
 				ParsedCategory:     c.Category,
 				ParsedSummary:      "",
-				IsBreakingChange:   false,
+				IsBreakingChange:   breakingChange != "",
 				Parser:             "closingPattern",
 				ParsedType:         c.Verb.String(),
+				BreakingChange:     breakingChange,
+				Trailers:           trailerValues,
 			}
 			cds = append(cds, cd)
 			fmt.Printf("extracted %s \n", cd.String())
 		}
+
+		category, isBreaking := entities.UNKNOWN, breakingChange != ""
+		if cc != nil {
+			category = cc.Category
+			isBreaking = isBreaking || cc.IsBreaking
+		}
+		for _, t := range trailers {
+			if !issueRefTrailers[strings.ToLower(t.Token)] {
+				continue
+			}
+			for _, key := range parsers.SplitKeys(t.Value) {
+				issueDetails := r.itParser.Parse(key)
+				cd := entities.ParsedRepoRecord{
+					RepoRecord:         commit,
+					ParsedKey:          issueDetails.Ref(),
+					ParsedProject:      issueDetails.Project,
+					ParsedIssueTracker: issueDetails.IssueTracker,
+					ParsedCategory:     category,
+					IsBreakingChange:   isBreaking,
+					Parser:             "trailer",
+					ParsedType:         strings.ToLower(t.Token),
+					BreakingChange:     breakingChange,
+					Trailers:           trailerValues,
+				}
+				if found[cd.ParsedKey] {
+					continue
+				}
+				found[cd.ParsedKey] = true
+				cds = append(cds, cd)
+				fmt.Printf("extracted %s \n", cd.String())
+			}
+		}
 	}
 
 	return cds, nil
 }
+
+// parseRevert returns the records of the commit reverting a commit of an
+// earlier release, with the issues referenced by the title of the reverted commit.
+func (r Repo) parseRevert(commit entities.RepoRecord, revert parsers.Revert) []entities.ParsedRepoRecord {
+	cd := entities.ParsedRepoRecord{
+		RepoRecord:         commit,
+		ParsedSummary:      revert.Title,
+		ParsedCategory:     entities.REVERT,
+		ParsedIssueTracker: parsers.UNKNOWN_ISSUE_TRACKER,
+		Parser:             "revertParser",
+		ParsedType:         "revert",
+	}
+
+	cc := r.conventionalParser.Parse(revert.Title)
+	if cc == nil && r.customParser != nil {
+		cc = r.customParser.Parse(revert.Title)
+	}
+	if cc == nil || cc.Scope == "" {
+		return []entities.ParsedRepoRecord{cd}
+	}
+
+	cd.ParsedSummary = cc.Subject
+	var cds []entities.ParsedRepoRecord
+	for _, issueDetails := range r.scopeIssues(*cc) {
+		cd.ParsedKey = issueDetails.Ref()
+		cd.ParsedProject = issueDetails.Project
+		cd.ParsedIssueTracker = issueDetails.IssueTracker
+		cds = append(cds, cd)
+	}
+
+	return cds
+}
+
+// revertedRecords returns the ids of the commits and merge requests reverted by
+// other records of the list, together with the ids of the reverts, as they
+// cancel each other out. A revert reverted in turn doesn't cancel the record
+// it reverts. The revert of the merge or squash commit of a merge request
+// reverts the commits it merged too.
+func revertedRecords(records []entities.RepoRecord) map[string]bool {
+	revertedBy := map[string][]string{}
+	for _, c := range records {
+		revert := parsers.ParseRevert(c.Title, c.Message)
+		if revert == nil {
+			continue
+		}
+		for _, target := range revertTargets(records, c.ID, *revert) {
+			revertedBy[target] = append(revertedBy[target], c.ID)
+		}
+	}
+
+	memo := map[string]bool{}
+	var isCancelled func(id string) bool
+	isCancelled = func(id string) bool {
+		if v, ok := memo[id]; ok {
+			return v
+		}
+		memo[id] = false
+		for _, revertID := range revertedBy[id] {
+			if !isCancelled(revertID) {
+				memo[id] = true
+				break
+			}
+		}
+		return memo[id]
+	}
+
+	reverted := map[string]bool{}
+	for id, reverts := range revertedBy {
+		if !isCancelled(id) {
+			continue
+		}
+		reverted[id] = true
+		for _, revertID := range reverts {
+			if !isCancelled(revertID) {
+				reverted[revertID] = true
+			}
+		}
+	}
+	for _, mr := range records {
+		if slices.ContainsFunc(mr.MergeCommitIDs, func(id string) bool { return reverted[id] }) {
+			for _, id := range mr.CommitIDs {
+				reverted[id] = true
+			}
+		}
+	}
+
+	return reverted
+}
+
+// revertTargets returns the ids of the records reverted by the revert with the
+// id: the commit referenced by the revert or, when it references none, the
+// first commit and the first merge request with the reverted title.
+func revertTargets(records []entities.RepoRecord, id string, revert parsers.Revert) []string {
+	if revert.CommitID != "" {
+		for _, t := range records {
+			if t.ID != id && t.Origin != "merge_request" && strings.HasPrefix(strings.ToLower(t.ID), revert.CommitID) {
+				return []string{t.ID}
+			}
+		}
+		return nil
+	}
+
+	var targets []string
+	origins := map[string]bool{}
+	for _, t := range records {
+		if t.ID != id && !origins[t.Origin] && strings.TrimSpace(t.Title) == revert.Title {
+			origins[t.Origin] = true
+			targets = append(targets, t.ID)
+		}
+	}
+
+	return targets
+}
+
+// isReverted reports whether the record is reverted within the release. A merge
+// request is reverted when its merge or squash commit is, or when all the
+// other commits it merged are.
+func isReverted(record entities.RepoRecord, reverted map[string]bool) bool {
+	if len(reverted) == 0 {
+		return false
+	}
+	if reverted[record.ID] {
+		return true
+	}
+	for _, id := range record.MergeCommitIDs {
+		if reverted[id] {
+			return true
+		}
+	}
+
+	merged := 0
+	for _, id := range record.CommitIDs {
+		if slices.Contains(record.MergeCommitIDs, id) {
+			continue
+		}
+		if !reverted[id] {
+			return false
+		}
+		merged++
+	}
+
+	return merged > 0
+}
+
+// issueRefTrailers are the tokens of the trailers referencing issues, whose keys
+// are extracted like the ones of the scope. The closing trailers, like Closes,
+// are extracted by the closing pattern.
+var issueRefTrailers = map[string]bool{"refs": true, "jira": true}
+
+// footer returns the description of the breaking change and the values of the
+// trailers by their lowercase token.
+func footer(trailers []parsers.Trailer) (string, map[string][]string) {
+	if len(trailers) == 0 {
+		return "", nil
+	}
+
+	var breakingChange string
+	values := map[string][]string{}
+	for _, t := range trailers {
+		if t.IsBreakingChange() && breakingChange == "" {
+			breakingChange = t.Value
+		}
+		token := strings.ToLower(t.Token)
+		values[token] = append(values[token], t.Value)
+	}
+
+	return breakingChange, values
+}
+
+// scopeIssues returns the issues referenced by the scope of the commit. When
+// the scope includes several keys, each one is routed to its issue tracker and
+// the parts not matching any issue pattern are ignored. The scope is taken as a
+// whole when none of its parts is an issue key.
+func (r Repo) scopeIssues(cc parsers.ConventionalCommit) []*parsers.IssueDetail {
+	var issues []*parsers.IssueDetail
+	scopes := cc.Scopes(r.scopeSeparators)
+	if len(scopes) > 1 {
+		for _, scope := range scopes {
+			issueDetails := r.itParser.Parse(scope)
+			if issueDetails.IssueTracker != parsers.UNKNOWN_ISSUE_TRACKER {
+				issues = append(issues, issueDetails)
+			}
+		}
+	}
+	if len(issues) == 0 {
+		issues = append(issues, r.itParser.Parse(cc.Scope))
+	}
+
+	return issues
+}
@@ -492,6 +492,9 @@ func TestGitLabMRParse(t *testing.T) {
 					assertParam(t, "target_branch", "main", params)
 					resp := generalMRResponse(tt.mrDescription)
 					w.Write([]byte(resp))
+				} else if r.URL.Path == "/api/v4/projects/123/merge_requests/1/commits" {
+					w.WriteHeader(200)
+					w.Write([]byte("[]"))
 				} else {
 					http.Error(w, "Not found", http.StatusNotFound)
 				}
@@ -519,6 +522,40 @@ func TestGitLabMRParse(t *testing.T) {
 	}
 }
 
+func TestGitLabMRLinkedToCommits(t *testing.T) {
+	gitsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/projects/123/repository/compare":
+			w.Write([]byte(`{"commits": [{"id": "commit0", "title": "[AAA-1] first"}, {"id": "commit1", "title": "[AAA-2] second"}]}`))
+		case "/api/v4/projects/123/merge_requests":
+			w.Write([]byte(`[{"id": 10, "iid": 1, "title": "[AAA-1] the MR", "sha": "other", "web_url": "http://gitlab.example.com/my/repo/merge_request/1"}]`))
+		case "/api/v4/projects/123/merge_requests/1/commits":
+			w.Write([]byte(`[{"id": "commit0"}]`))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitsrv.Close()
+
+	gc, err := clients.NewGitLab(gitsrv.URL, "token")
+	assert.NoError(t, err, "NewGit error must be nil")
+
+	gp, err := repo.New(gc, []parsers.IssuePattern{{IssueTracker: "jira", Pattern: `\w+-\d+`}},
+		repo.WithCustomPattern(`\[(?P<scope>[^\]]*)\](?P<subject>.*)`))
+	assert.NoError(t, err, "NewGit error must be nil")
+
+	gotCds, err := gp.GetParsedRecords("123", "from", "to", "main")
+	assert.NoError(t, err, "Parse error must be nil")
+	assert.Equal(t, 3, len(gotCds))
+
+	assert.Equal(t, "commit0", gotCds[0].ID)
+	assert.Equal(t, "1", gotCds[0].MergeRequestID)
+	assert.Equal(t, "http://gitlab.example.com/my/repo/merge_request/1", gotCds[0].MergeRequestURL)
+	assert.Equal(t, "commit1", gotCds[1].ID)
+	assert.Equal(t, "", gotCds[1].MergeRequestID)
+	assert.Equal(t, "merge_request", gotCds[2].Origin)
+}
+
 func ptrTimeDate(t time.Time) *time.Time {
 	return &t
 }
@@ -545,6 +582,7 @@ func generalMRRepoRecord(msg string) entities.RepoRecord {
 		CreatedAt: ptrTimeDate(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
 		Origin:    "merge_request",
 		WebURL:    "http://gitlab.example.com/my/repo/merge_request/1",
+		CommitIDs: []string{"commit0"},
 	}
 }
 
@@ -568,3 +606,207 @@ func assertParam(t *testing.T, key, value string, params url.Values) {
 		t.Errorf("%s parameter not found or not as expected: want %s got %s", key, value, p[0])
 	}
 }
+
+func TestCommitParseProjectReferences(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/projects/123/repository/compare" {
+			w.Write([]byte(`{"commits": [
+				{"id": "commit1", "title": "feat(platform/backlog#12): shared feature", "message": "feat(platform/backlog#12): shared feature"},
+				{"id": "commit2", "title": "fix: a bug", "message": "fix: a bug\n\nCloses #3 and https://gitlab.example.com/platform/backlog/-/issues/4"}
+			]}`))
+		} else {
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	gc, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	gp, err := repo.New(gc, []parsers.IssuePattern{
+		{IssueTracker: "git", Pattern: `https?://[^/\s]+/(?P<project>[\w.-]+(?:/[\w.-]+)+?)(?:/-)?/(?:issues|pull)/(?P<key>\d+)`},
+		{IssueTracker: "git", Pattern: `(?:(?P<project>[\w.-]+(?:/[\w.-]+)+))?#(?P<key>\d+)`},
+	})
+	assert.NoError(t, err)
+
+	records, err := gp.GetParsedRecords("123", "from", "to", "")
+	assert.NoError(t, err)
+
+	var got [][]string
+	for _, r := range records {
+		got = append(got, []string{r.ParsedKey, r.ParsedProject, r.ParsedIssueTracker, r.Parser})
+	}
+	assert.Equal(t, [][]string{
+		{"platform/backlog#12", "platform/backlog", "GIT", "conventionalParser"},
+		{"3", "", "GIT", "closingPattern"},
+		{"platform/backlog#4", "platform/backlog", "GIT", "closingPattern"},
+	}, got)
+}
+
+func TestCommitParseScopeWithSeveralKeys(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/projects/123/repository/compare" {
+			w.Write([]byte(`{"commits": [
+				{"id": "commit1", "title": "feat(ABC-1,ABC-2): shared feature", "message": "feat(ABC-1,ABC-2): shared feature"},
+				{"id": "commit2", "title": "fix(ABC-1 #44): a bug", "message": "fix(ABC-1 #44): a bug"},
+				{"id": "commit3", "title": "feat(api, ABC-3): an endpoint", "message": "feat(api, ABC-3): an endpoint"},
+				{"id": "commit4", "title": "feat(user profile): a page", "message": "feat(user profile): a page"}
+			]}`))
+		} else {
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	gc, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	gp, err := repo.New(gc, []parsers.IssuePattern{
+		{IssueTracker: "jira", Pattern: `[A-Z]+-\d+`},
+		{IssueTracker: "git", Pattern: `#(?P<key>\d+)`},
+	})
+	assert.NoError(t, err)
+
+	records, err := gp.GetParsedRecords("123", "from", "to", "")
+	assert.NoError(t, err)
+
+	var got [][]string
+	for _, r := range records {
+		got = append(got, []string{r.ID, r.ParsedKey, r.ParsedIssueTracker, r.ParsedType})
+	}
+	assert.Equal(t, [][]string{
+		{"commit1", "ABC-1", "JIRA", "feat"},
+		{"commit1", "ABC-2", "JIRA", "feat"},
+		{"commit2", "44", "GIT", "fix"},
+		{"commit3", "ABC-3", "JIRA", "feat"},
+		{"commit4", "user profile", "NONE", "feat"},
+	}, got)
+}
+
+func TestCommitParseTrailers(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/projects/123/repository/compare" {
+			w.Write([]byte(`{"commits": [
+				{"id": "commit1", "title": "feat(ABC-1): new endpoint", "message": "feat(ABC-1): new endpoint\n\nBREAKING CHANGE: the old endpoint is removed\nRefs: ABC-1, ABC-2\nJira: ABC-3\nCloses: ABC-4\nRelease-Note: a new endpoint"}
+			]}`))
+		} else {
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	gc, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	gp, err := repo.New(gc, []parsers.IssuePattern{{IssueTracker: "jira", Pattern: `[A-Z]+-\d+`}})
+	assert.NoError(t, err)
+
+	records, err := gp.GetParsedRecords("123", "from", "to", "")
+	assert.NoError(t, err)
+
+	var got [][]string
+	for _, r := range records {
+		got = append(got, []string{r.ParsedKey, r.Parser, r.ParsedType, r.ParsedCategory.String()})
+		assert.Equal(t, "the old endpoint is removed", r.BreakingChange)
+		assert.Equal(t, map[string][]string{
+			"breaking change": {"the old endpoint is removed"},
+			"refs":            {"ABC-1, ABC-2"},
+			"jira":            {"ABC-3"},
+			"closes":          {"ABC-4"},
+			"release-note":    {"a new endpoint"},
+		}, r.Trailers)
+		assert.True(t, r.IsBreakingChange, r.ParsedKey)
+	}
+	assert.Equal(t, [][]string{
+		{"ABC-1", "conventionalParser", "feat", "FEATURE"},
+		{"ABC-4", "closingPattern", "close", "FEATURE"},
+		{"ABC-2", "trailer", "refs", "FEATURE"},
+		{"ABC-3", "trailer", "jira", "FEATURE"},
+	}, got)
+}
+
+func TestCommitParseReverts(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/projects/123/repository/compare" {
+			w.Write([]byte(`{"commits": [
+				{"id": "aaa1110", "title": "feat(ABC-1): reverted feature", "message": "feat(ABC-1): reverted feature"},
+				{"id": "bbb2220", "title": "feat(ABC-2): kept feature", "message": "feat(ABC-2): kept feature"},
+				{"id": "ccc3330", "title": "Revert \"feat(ABC-1): reverted feature\"", "message": "Revert \"feat(ABC-1): reverted feature\"\n\nThis reverts commit aaa1110."},
+				{"id": "ddd4440", "title": "Revert \"feat(ABC-2): kept feature\"", "message": "Revert \"feat(ABC-2): kept feature\"\n\nThis reverts commit bbb2220."},
+				{"id": "eee5550", "title": "Revert \"Revert \"feat(ABC-2): kept feature\"\"", "message": "Revert \"Revert \"feat(ABC-2): kept feature\"\"\n\nThis reverts commit ddd4440."},
+				{"id": "fff6660", "title": "Revert \"fix(ABC-3): old fix\"", "message": "Revert \"fix(ABC-3): old fix\"\n\nThis reverts commit 0123456789abcdef."}
+			]}`))
+		} else {
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	gc, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	gp, err := repo.New(gc, []parsers.IssuePattern{{IssueTracker: "jira", Pattern: `[A-Z]+-\d+`}})
+	assert.NoError(t, err)
+
+	records, err := gp.GetParsedRecords("123", "from", "to", "")
+	assert.NoError(t, err)
+
+	var got [][]string
+	for _, r := range records {
+		got = append(got, []string{r.ID, r.ParsedKey, r.ParsedCategory.String(), r.Parser, r.ParsedSummary})
+	}
+	assert.Equal(t, [][]string{
+		{"bbb2220", "ABC-2", "FEATURE", "conventionalParser", "kept feature"},
+		{"fff6660", "ABC-3", "REVERT", "revertParser", "old fix"},
+	}, got)
+}
+
+func TestMRParseReverts(t *testing.T) {
+	gitSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/projects/123/repository/compare":
+			w.Write([]byte(`{"commits": [
+				{"id": "aaa1110", "title": "feat(ABC-1): reverted feature", "message": "feat(ABC-1): reverted feature"},
+				{"id": "bbb2220", "title": "Merge branch 'feature' into 'main'", "message": "Merge branch 'feature' into 'main'\n\nfeat(ABC-1): reverted feature\n\nSee merge request !1"},
+				{"id": "ccc3330", "title": "Revert \"Merge branch 'feature' into 'main'\"", "message": "Revert \"Merge branch 'feature' into 'main'\"\n\nThis reverts commit bbb2220, reversing\nchanges made to 9876543."},
+				{"id": "ddd4440", "title": "Merge branch 'revert-feature' into 'main'", "message": "Merge branch 'revert-feature' into 'main'\n\nSee merge request !2"},
+				{"id": "eee5550", "title": "fix(ABC-2): kept fix", "message": "fix(ABC-2): kept fix"},
+				{"id": "fff6660", "title": "feat(ABC-3): reverted by title", "message": "feat(ABC-3): reverted by title"},
+				{"id": "abc1110", "title": "Revert \"feat(ABC-3): reverted by title\"", "message": "Revert \"feat(ABC-3): reverted by title\""},
+				{"id": "abc2220", "title": "feat(ABC-4): squashed feature", "message": "feat(ABC-4): squashed feature"},
+				{"id": "abc3330", "title": "Revert \"feat(ABC-4): squashed feature\"", "message": "Revert \"feat(ABC-4): squashed feature\"\n\nThis reverts commit abc2220."},
+				{"id": "abc4440", "title": "Merge branch 'revert-squashed' into 'main'", "message": "Merge branch 'revert-squashed' into 'main'\n\nSee merge request !5"}
+			]}`))
+		case "/api/v4/projects/123/merge_requests":
+			w.Write([]byte(`[
+				{"id": 101, "iid": 1, "title": "feat(ABC-1): reverted feature", "sha": "aaa1110", "merge_commit_sha": "bbb2220"},
+				{"id": 102, "iid": 2, "title": "Revert the feature", "sha": "ccc3330", "merge_commit_sha": "ddd4440"},
+				{"id": 103, "iid": 3, "title": "fix(ABC-2): kept fix", "sha": "9999999", "squash_commit_sha": "eee5550"},
+				{"id": 104, "iid": 4, "title": "feat(ABC-4): squashed feature", "sha": "8888888", "squash_commit_sha": "abc2220"},
+				{"id": 105, "iid": 5, "title": "Revert \"feat(ABC-4): squashed feature\"", "description": "This reverts merge request !4", "sha": "abc3330", "merge_commit_sha": "abc4440"}
+			]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer gitSrv.Close()
+
+	gc, err := clients.NewGitLab(gitSrv.URL, "token")
+	assert.NoError(t, err)
+
+	gp, err := repo.New(gc, []parsers.IssuePattern{{IssueTracker: "jira", Pattern: `[A-Z]+-\d+`}})
+	assert.NoError(t, err)
+
+	records, err := gp.GetParsedRecords("123", "from", "to", "main")
+	assert.NoError(t, err)
+
+	var got [][]string
+	for _, r := range records {
+		got = append(got, []string{r.ID, r.ParsedKey, r.ParsedCategory.String(), r.Parser})
+	}
+	assert.Equal(t, [][]string{
+		{"eee5550", "ABC-2", "BUG_FIX", "conventionalParser"},
+		{"103", "ABC-2", "BUG_FIX", "conventionalParser"},
+	}, got)
+}